@@ -0,0 +1,188 @@
+// Gabriel Seijas 19-00036
+package comandos
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Comando describe una orden del REPL. R es lo que devuelve al ejecutarse, por ejemplo si
+// terminó bien o si hay que salir.
+type Comando[R any] struct {
+	Nombre      string                       // Una o más palabras, como "LISTAR" o "DEFINIR PROGRAMA"
+	Argumentos  string                       // Cómo se escriben los argumentos, como "<nombre> [costo]"
+	Descripcion string                       // Una línea para AYUDA
+	Min, Max    int                          // Cuántos argumentos acepta; Max negativo es sin límite
+	Valido      func(args []string) bool     // Revisión extra de los argumentos, opcional
+	Completar   func(args []string) []string // Candidatos para el argumento que sigue a args, opcional
+	Ejecutar    func(args []string) R
+}
+
+// Uso da el nombre del comando con sus argumentos, como se muestra en los errores y la ayuda
+func (c Comando[R]) Uso() string {
+	if c.Argumentos == "" {
+		return c.Nombre
+	}
+	return c.Nombre + " " + c.Argumentos
+}
+
+// acepta indica si la cantidad y la forma de los argumentos sirven para el comando
+func (c Comando[R]) acepta(args []string) bool {
+	if len(args) < c.Min || (c.Max >= 0 && len(args) > c.Max) {
+		return false
+	}
+	return c.Valido == nil || c.Valido(args)
+}
+
+// ErrorUso es el error de llamar a un comando con argumentos que no le sirven
+type ErrorUso struct {
+	Uso string
+}
+
+func (e *ErrorUso) Error() string {
+	return "Uso: " + e.Uso
+}
+
+// ErrorDesconocido es el error de escribir un comando que no existe. Si la primera palabra
+// es de un grupo, como DEFINIR, Grupo es esa palabra y Nombre la que no se reconoció.
+// Opciones son los nombres que sí se aceptaban en ese lugar.
+type ErrorDesconocido struct {
+	Grupo    string
+	Nombre   string
+	Opciones []string
+}
+
+func (e *ErrorDesconocido) Error() string {
+	if e.Grupo != "" {
+		return fmt.Sprintf("%s no acepta '%s', usa %s", e.Grupo, e.Nombre, Lista(e.Opciones))
+	}
+	return fmt.Sprintf("comando '%s' desconocido", e.Nombre)
+}
+
+// Lista une las palabras como en una oración: "A", "A o B", "A, B o C"
+func Lista(palabras []string) string {
+	if len(palabras) <= 1 {
+		return strings.Join(palabras, "")
+	}
+	return strings.Join(palabras[:len(palabras)-1], ", ") + " o " + palabras[len(palabras)-1]
+}
+
+// Conjunto son los comandos de un REPL, en el orden en que se muestran en la ayuda
+type Conjunto[R any] struct {
+	comandos []Comando[R]
+}
+
+// NuevoConjunto arma el conjunto con los comandos dados
+func NuevoConjunto[R any](comandos ...Comando[R]) *Conjunto[R] {
+	return &Conjunto[R]{comandos: comandos}
+}
+
+// Agregar suma comandos al final; sirve para los que necesitan al conjunto, como AYUDA
+func (c *Conjunto[R]) Agregar(comandos ...Comando[R]) {
+	c.comandos = append(c.comandos, comandos...)
+}
+
+// Comandos devuelve los comandos en el orden de la ayuda
+func (c *Conjunto[R]) Comandos() []Comando[R] {
+	return slices.Clone(c.comandos)
+}
+
+// coincide indica si las primeras palabras son el nombre del comando, sin importar mayúsculas
+func coincide(nombre []string, palabras []string) bool {
+	if len(palabras) < len(nombre) {
+		return false
+	}
+	for i, parte := range nombre {
+		if !strings.EqualFold(parte, palabras[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// buscar encuentra el comando con el nombre más largo que coincide con el principio de la
+// línea y devuelve cuántas palabras ocupa el nombre
+func (c *Conjunto[R]) buscar(palabras []string) (Comando[R], int, bool) {
+	var mejor Comando[R]
+	largo := 0
+	for _, cmd := range c.comandos {
+		nombre := strings.Fields(cmd.Nombre)
+		if len(nombre) > largo && coincide(nombre, palabras) {
+			mejor, largo = cmd, len(nombre)
+		}
+	}
+	return mejor, largo, largo > 0
+}
+
+// siguientes da, sin repetir, las palabras que pueden seguir a las dadas en algún nombre
+func (c *Conjunto[R]) siguientes(palabras []string) []string {
+	var opciones []string
+	for _, cmd := range c.comandos {
+		nombre := strings.Fields(cmd.Nombre)
+		if len(nombre) > len(palabras) && coincide(nombre[:len(palabras)], palabras) && !slices.Contains(opciones, nombre[len(palabras)]) {
+			opciones = append(opciones, nombre[len(palabras)])
+		}
+	}
+	return opciones
+}
+
+// Ejecutar separa la línea y corre el comando. Una línea vacía no hace nada y devuelve el
+// valor cero de R. Los errores son de escritura: comillas sin cerrar, *ErrorDesconocido o
+// *ErrorUso; lo que pase dentro del comando lo informa el propio comando en R.
+func (c *Conjunto[R]) Ejecutar(linea string) (R, error) {
+	palabras, err := Separar(linea)
+	if err != nil {
+		var cero R
+		return cero, err
+	}
+	return c.EjecutarPalabras(palabras)
+}
+
+// EjecutarPalabras es como Ejecutar pero con la línea ya separada
+func (c *Conjunto[R]) EjecutarPalabras(palabras []string) (R, error) {
+	var cero R
+	if len(palabras) == 0 {
+		return cero, nil
+	}
+	cmd, largo, ok := c.buscar(palabras)
+	if !ok {
+		grupo := strings.ToUpper(palabras[0])
+		opciones := c.siguientes(palabras[:1])
+		switch {
+		case len(opciones) == 0:
+			return cero, &ErrorDesconocido{Nombre: palabras[0], Opciones: c.siguientes(nil)}
+		case len(palabras) == 1:
+			return cero, &ErrorUso{Uso: grupo + " <tipo> [argumentos]"}
+		default:
+			return cero, &ErrorDesconocido{Grupo: grupo, Nombre: palabras[1], Opciones: opciones}
+		}
+	}
+	args := palabras[largo:]
+	if !cmd.acepta(args) {
+		return cero, &ErrorUso{Uso: cmd.Uso()}
+	}
+	return cmd.Ejecutar(args), nil
+}
+
+// Ayuda escribe el uso y la descripción de cada comando. Con palabras solo muestra los
+// comandos cuyo nombre empieza con ellas, por ejemplo todos los DEFINIR.
+func (c *Conjunto[R]) Ayuda(w io.Writer, palabras []string) error {
+	var elegidos []Comando[R]
+	for _, cmd := range c.comandos {
+		if nombre := strings.Fields(cmd.Nombre); len(palabras) <= len(nombre) && coincide(nombre[:len(palabras)], palabras) {
+			elegidos = append(elegidos, cmd)
+		}
+	}
+	if len(elegidos) == 0 {
+		return &ErrorDesconocido{Nombre: strings.Join(palabras, " "), Opciones: c.siguientes(nil)}
+	}
+	for _, cmd := range elegidos {
+		fmt.Fprintf(w, "  %s\n", cmd.Uso())
+		if cmd.Descripcion != "" {
+			fmt.Fprintf(w, "      %s\n", cmd.Descripcion)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Gabriel Seijas 19-00036
+package comandos
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// conjuntoDePrueba arma un REPL chico que devuelve el nombre del comando y sus argumentos
+func conjuntoDePrueba() *Conjunto[string] {
+	eco := func(nombre string) func([]string) string {
+		return func(args []string) string { return nombre + ":" + strings.Join(args, "|") }
+	}
+	return NuevoConjunto(
+		Comando[string]{Nombre: "DEFINIR PROGRAMA", Argumentos: "<nombre> <lenguaje>", Descripcion: "Define un programa.", Min: 2, Max: 2, Ejecutar: eco("programa")},
+		Comando[string]{Nombre: "DEFINIR INTERPRETE", Argumentos: "<base> <lenguaje> [costo]", Min: 2, Max: 3, Ejecutar: eco("interprete")},
+		Comando[string]{Nombre: "CODIGO", Argumentos: "<nombre> <código...>", Min: 2, Max: -1, Ejecutar: eco("codigo")},
+		Comando[string]{
+			Nombre: "LISTAR", Argumentos: "[PROGRAMAS|LENGUAJES]", Max: 1,
+			Valido:    func(args []string) bool { return len(args) == 0 || args[0] != "NADA" },
+			Completar: func(args []string) []string { return []string{"PROGRAMAS", "LENGUAJES"} },
+			Ejecutar:  eco("listar"),
+		},
+	)
+}
+
+// Prueba que se encuentra el comando sin importar mayúsculas y con nombres de varias palabras
+func TestEjecutar(t *testing.T) {
+	c := conjuntoDePrueba()
+	casos := []struct {
+		linea, esperado string
+	}{
+		{`definir programa app GO`, "programa:app|GO"},
+		{`DEFINIR Interprete GO LOCAL 2`, "interprete:GO|LOCAL|2"},
+		{`DEFINIR PROGRAMA "mi app" GO`, "programa:mi app|GO"},
+		{`CODIGO app 2 3 + .`, "codigo:app|2|3|+|."},
+		{`listar`, "listar:"},
+		{``, ""},
+	}
+	for _, caso := range casos {
+		if obtenido, err := c.Ejecutar(caso.linea); err != nil || obtenido != caso.esperado {
+			t.Errorf("Ejecutar(%q) = %q, %v; se esperaba %q", caso.linea, obtenido, err, caso.esperado)
+		}
+	}
+}
+
+// Prueba los errores de uso, de comandos desconocidos y de comillas
+func TestEjecutarErrores(t *testing.T) {
+	c := conjuntoDePrueba()
+	usos := []struct {
+		linea, uso string
+	}{
+		{"DEFINIR PROGRAMA app", "Uso: DEFINIR PROGRAMA <nombre> <lenguaje>"},
+		{"DEFINIR INTERPRETE a b c d", "Uso: DEFINIR INTERPRETE <base> <lenguaje> [costo]"},
+		{"LISTAR NADA", "Uso: LISTAR [PROGRAMAS|LENGUAJES]"},
+		{"definir", "Uso: DEFINIR <tipo> [argumentos]"},
+	}
+	for _, caso := range usos {
+		var uso *ErrorUso
+		if _, err := c.Ejecutar(caso.linea); !errors.As(err, &uso) || err.Error() != caso.uso {
+			t.Errorf("Ejecutar(%q) dio %v, se esperaba %q", caso.linea, err, caso.uso)
+		}
+	}
+
+	var desconocido *ErrorDesconocido
+	if _, err := c.Ejecutar("BORRAR x"); !errors.As(err, &desconocido) || desconocido.Grupo != "" || desconocido.Nombre != "BORRAR" {
+		t.Errorf("Se esperaba un comando desconocido: %v", err)
+	}
+	if !slices.Equal(desconocido.Opciones, []string{"DEFINIR", "CODIGO", "LISTAR"}) {
+		t.Errorf("Opciones incorrectas: %v", desconocido.Opciones)
+	}
+	if _, err := c.Ejecutar("DEFINIR TRADUCTOR a b c"); !errors.As(err, &desconocido) || err.Error() != "DEFINIR no acepta 'TRADUCTOR', usa PROGRAMA o INTERPRETE" {
+		t.Errorf("Se esperaba un tipo desconocido: %v", err)
+	}
+	if _, err := c.Ejecutar(`DEFINIR PROGRAMA "mi app GO`); err == nil || !strings.Contains(err.Error(), "comillas") {
+		t.Errorf("Se esperaba un error por las comillas sin cerrar: %v", err)
+	}
+}
+
+// Prueba que la ayuda muestra todos los comandos o solo los de un grupo
+func TestAyuda(t *testing.T) {
+	c := conjuntoDePrueba()
+	var out bytes.Buffer
+	c.Ayuda(&out, []string{"definir"})
+	esperado := "  DEFINIR PROGRAMA <nombre> <lenguaje>\n      Define un programa.\n  DEFINIR INTERPRETE <base> <lenguaje> [costo]\n"
+	if out.String() != esperado {
+		t.Errorf("Ayuda de DEFINIR incorrecta:\n%s", out.String())
+	}
+
+	out.Reset()
+	c.Ayuda(&out, nil)
+	if strings.Count(out.String(), "\n") != 5 || !strings.Contains(out.String(), "  CODIGO <nombre> <código...>\n") {
+		t.Errorf("Ayuda completa incorrecta:\n%s", out.String())
+	}
+	if err := c.Ayuda(&out, []string{"BORRAR"}); err == nil {
+		t.Errorf("La ayuda de un comando que no existe debería dar error")
+	}
+}
+
+// Prueba que se completan los nombres de los comandos y los argumentos
+func TestCompletar(t *testing.T) {
+	c := conjuntoDePrueba()
+	casos := []struct {
+		linea      string
+		inicio     int
+		candidatos []string
+	}{
+		{"", 0, []string{"CODIGO", "DEFINIR", "LISTAR"}},
+		{"de", 0, []string{"DEFINIR"}},
+		{"DEFINIR ", 8, []string{"INTERPRETE", "PROGRAMA"}},
+		{"definir p", 8, []string{"PROGRAMA"}},
+		{"LISTAR l", 7, []string{"LENGUAJES"}},
+		{"CODIGO x", 7, nil},
+		{"z", 0, nil},
+	}
+	for _, caso := range casos {
+		inicio, candidatos := c.Completar(caso.linea)
+		if inicio != caso.inicio || !slices.Equal(candidatos, caso.candidatos) {
+			t.Errorf("Completar(%q) = %d, %v; se esperaba %d, %v", caso.linea, inicio, candidatos, caso.inicio, caso.candidatos)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Gabriel Seijas 19-00036
+package comandos
+
+import (
+	"slices"
+	"strings"
+)
+
+// Completar da las formas de terminar la última palabra de la línea y el byte donde empieza
+// esa palabra, que es lo que hay que reemplazar. Primero se completan los nombres de los
+// comandos y después los argumentos que sugiera el comando. No se distinguen mayúsculas y
+// los candidatos salen ordenados y entre comillas si hace falta.
+func (c *Conjunto[R]) Completar(linea string) (int, []string) {
+	palabras, abierta, inicio := separar(linea)
+	actual := ""
+	if inicio < len(linea) || abierta != 0 {
+		actual = palabras[len(palabras)-1]
+		palabras = palabras[:len(palabras)-1]
+	}
+
+	opciones := c.siguientes(palabras)
+	if cmd, largo, ok := c.buscar(palabras); ok && cmd.Completar != nil {
+		opciones = append(opciones, cmd.Completar(palabras[largo:])...)
+	}
+
+	var candidatos []string
+	for _, opcion := range opciones {
+		if strings.HasPrefix(strings.ToUpper(opcion), strings.ToUpper(actual)) {
+			candidatos = append(candidatos, Citar(opcion))
+		}
+	}
+	slices.Sort(candidatos)
+	return inicio, slices.Compact(candidatos)
+}
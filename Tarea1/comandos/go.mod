@@ -0,0 +1,3 @@
+module comandos
+
+go 1.25.2
@@ -0,0 +1,164 @@
+// Gabriel Seijas 19-00036
+package comandos
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Completador da dónde empieza la palabra a completar y los candidatos, como Conjunto.Completar
+type Completador func(linea string) (int, []string)
+
+// Lector lee las líneas de un REPL. Si la entrada es una terminal la edita él mismo para
+// completar con Tab; si es un archivo o una tubería lee líneas completas.
+type Lector struct {
+	salida    io.Writer
+	completar Completador
+	lineas    *bufio.Scanner // Modo de líneas completas
+	runas     *bufio.Reader  // Modo de edición
+	restaurar func()
+}
+
+// NuevoLector prepara la lectura. completar puede ser nil si no se quiere completar. Hay que
+// llamar a Cerrar al terminar para devolver la terminal a su modo normal.
+func NuevoLector(entrada io.Reader, salida io.Writer, completar Completador) *Lector {
+	l := &Lector{salida: salida, completar: completar}
+	if archivo, ok := entrada.(*os.File); ok && completar != nil {
+		if restaurar, err := modoCrudo(archivo); err == nil {
+			l.restaurar = restaurar
+			l.runas = bufio.NewReader(archivo)
+			return l
+		}
+	}
+	l.lineas = bufio.NewScanner(entrada)
+	return l
+}
+
+// nuevoEditor lee siempre en modo de edición; sirve para probar el editor sin una terminal
+func nuevoEditor(entrada io.Reader, salida io.Writer, completar Completador) *Lector {
+	return &Lector{salida: salida, completar: completar, runas: bufio.NewReader(entrada)}
+}
+
+// Cerrar devuelve la terminal a como estaba
+func (l *Lector) Cerrar() {
+	if l.restaurar != nil {
+		l.restaurar()
+		l.restaurar = nil
+	}
+}
+
+// Leer muestra el prompt y devuelve la línea siguiente, o falso si se acabó la entrada
+func (l *Lector) Leer(prompt string) (string, bool) {
+	fmt.Fprint(l.salida, prompt)
+	if l.runas == nil {
+		if !l.lineas.Scan() {
+			return "", false
+		}
+		return l.lineas.Text(), true
+	}
+	return l.editar(prompt)
+}
+
+// editar lee carácter por carácter. Entiende Enter, borrar, Tab, Ctrl-C (descarta la línea)
+// y Ctrl-D (fin de la entrada si la línea está vacía); ignora las flechas y demás secuencias.
+func (l *Lector) editar(prompt string) (string, bool) {
+	var linea []rune
+	for {
+		r, _, err := l.runas.ReadRune()
+		if err != nil {
+			if len(linea) > 0 {
+				fmt.Fprintln(l.salida)
+				return string(linea), true
+			}
+			return "", false
+		}
+		switch r {
+		case '\n', '\r':
+			fmt.Fprintln(l.salida)
+			return string(linea), true
+		case 4: // Ctrl-D
+			if len(linea) == 0 {
+				fmt.Fprintln(l.salida)
+				return "", false
+			}
+		case 3: // Ctrl-C
+			fmt.Fprint(l.salida, "^C\n"+ultimaLinea(prompt))
+			linea = linea[:0]
+		case 127, '\b':
+			if len(linea) > 0 {
+				linea = linea[:len(linea)-1]
+				fmt.Fprint(l.salida, "\b \b")
+			}
+		case '\t':
+			linea = l.completarLinea(prompt, linea)
+		case 27: // Escape: se descarta la secuencia, como ESC [ A de la flecha arriba
+			l.saltarEscape()
+		default:
+			if r >= ' ' {
+				linea = append(linea, r)
+				fmt.Fprint(l.salida, string(r))
+			}
+		}
+	}
+}
+
+// saltarEscape consume el resto de una secuencia de escape de la terminal
+func (l *Lector) saltarEscape() {
+	if r, _, err := l.runas.ReadRune(); err != nil || r != '[' {
+		return
+	}
+	for {
+		r, _, err := l.runas.ReadRune()
+		if err != nil || (r >= '@' && r <= '~') {
+			return
+		}
+	}
+}
+
+// completarLinea reemplaza la palabra actual por el candidato si hay uno solo, o por lo que
+// todos tienen en común si eso agrega algo. Si no, muestra los candidatos debajo.
+func (l *Lector) completarLinea(prompt string, linea []rune) []rune {
+	texto := string(linea)
+	inicio, candidatos := l.completar(texto)
+	palabra := texto[inicio:]
+	nueva := texto
+	switch {
+	case len(candidatos) == 0:
+		fmt.Fprint(l.salida, "\a")
+		return linea
+	case len(candidatos) == 1:
+		nueva = texto[:inicio] + candidatos[0] + " "
+	case utf8.RuneCountInString(prefijoComun(candidatos)) > utf8.RuneCountInString(palabra):
+		nueva = texto[:inicio] + prefijoComun(candidatos)
+	default:
+		fmt.Fprintf(l.salida, "\n%s\n%s%s", strings.Join(candidatos, "  "), ultimaLinea(prompt), texto)
+		return linea
+	}
+	// Se borra la palabra escrita y se escribe la completa
+	fmt.Fprint(l.salida, strings.Repeat("\b \b", utf8.RuneCountInString(palabra))+nueva[inicio:])
+	return []rune(nueva)
+}
+
+// ultimaLinea da lo que se ve del prompt en la línea donde se escribe
+func ultimaLinea(prompt string) string {
+	return prompt[strings.LastIndex(prompt, "\n")+1:]
+}
+
+// prefijoComun da el principio que comparten todos los candidatos, sin importar mayúsculas,
+// escrito como en el primero
+func prefijoComun(candidatos []string) string {
+	comun := []rune(candidatos[0])
+	for _, c := range candidatos[1:] {
+		otro := []rune(c)
+		n := 0
+		for n < len(comun) && n < len(otro) && strings.EqualFold(string(comun[n]), string(otro[n])) {
+			n++
+		}
+		comun = comun[:n]
+	}
+	return string(comun)
+}
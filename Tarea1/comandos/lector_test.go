@@ -0,0 +1,54 @@
+// Gabriel Seijas 19-00036
+package comandos
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Prueba que fuera de una terminal se leen líneas completas y se muestra el prompt
+func TestLectorLineas(t *testing.T) {
+	var out bytes.Buffer
+	l := NuevoLector(strings.NewReader("uno\ndos\n"), &out, conjuntoDePrueba().Completar)
+	defer l.Cerrar()
+	for _, esperada := range []string{"uno", "dos"} {
+		if linea, ok := l.Leer("> "); !ok || linea != esperada {
+			t.Errorf("Leer = %q, %v; se esperaba %q", linea, ok, esperada)
+		}
+	}
+	if _, ok := l.Leer("> "); ok {
+		t.Errorf("La entrada debería haberse acabado")
+	}
+	if out.String() != "> > > " {
+		t.Errorf("Prompts incorrectos: %q", out.String())
+	}
+}
+
+// Prueba la edición: Tab completa, borrar quita la última letra, Ctrl-C descarta la línea y
+// las flechas se ignoran
+func TestEditor(t *testing.T) {
+	c := conjuntoDePrueba()
+	entrada := "def\tpro\tapp\x7f\x7fpp GO\n" + "basura\x03lis\x1b[At\t\n" + "\x04"
+	var out bytes.Buffer
+	l := nuevoEditor(strings.NewReader(entrada), &out, c.Completar)
+
+	for _, esperada := range []string{"DEFINIR PROGRAMA app GO", "LISTAR "} {
+		if linea, ok := l.Leer("> "); !ok || linea != esperada {
+			t.Errorf("Leer = %q, %v; se esperaba %q", linea, ok, esperada)
+		}
+	}
+	if _, ok := l.Leer("> "); ok {
+		t.Errorf("Ctrl-D con la línea vacía debería terminar la entrada")
+	}
+	if !strings.Contains(out.String(), "^C\n> ") {
+		t.Errorf("No se mostró el Ctrl-C: %q", out.String())
+	}
+
+	// Con varios candidatos sin nada en común se listan debajo
+	out.Reset()
+	l = nuevoEditor(strings.NewReader("DEFINIR \t\n"), &out, c.Completar)
+	if linea, _ := l.Leer("> "); linea != "DEFINIR " || !strings.Contains(out.String(), "\nINTERPRETE  PROGRAMA\n> DEFINIR ") {
+		t.Errorf("No se listaron los candidatos: %q, %q", linea, out.String())
+	}
+}
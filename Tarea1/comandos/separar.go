@@ -0,0 +1,87 @@
+// Gabriel Seijas 19-00036
+
+// Package comandos es la capa de comandos que comparten los REPL de la tarea: separa las
+// líneas respetando comillas, busca el comando, revisa cuántos argumentos tiene, arma la
+// ayuda y completa nombres con Tab.
+package comandos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Separar divide una línea en palabras. Las comillas dobles o simples agrupan palabras con
+// espacios, por ejemplo DEFINIR PROGRAMA "mi app" GO. Dentro de comillas dobles \" y \\
+// ponen una comilla o una barra; fuera de ellas la barra es un carácter normal, para que
+// las rutas de Windows se escriban tal cual.
+func Separar(linea string) ([]string, error) {
+	palabras, abierta, _ := separar(linea)
+	if abierta != 0 {
+		return nil, fmt.Errorf("falta cerrar las comillas %c", abierta)
+	}
+	return palabras, nil
+}
+
+// separar hace el trabajo de Separar sin fallar, para poder completar mientras se escribe:
+// devuelve las comillas que quedaron abiertas, si hay, y dónde empieza la última palabra (el
+// largo de la línea si termina en un espacio)
+func separar(linea string) ([]string, rune, int) {
+	var palabras []string
+	var actual strings.Builder
+	enPalabra := false
+	inicio := len(linea)
+	var comilla rune
+	escapada := false
+	for i, r := range linea {
+		switch {
+		case escapada:
+			if r != '"' && r != '\\' {
+				actual.WriteRune('\\')
+			}
+			actual.WriteRune(r)
+			escapada = false
+		case comilla == '"' && r == '\\':
+			escapada = true
+		case comilla != 0 && r == comilla:
+			comilla = 0
+		case comilla != 0:
+			actual.WriteRune(r)
+		case r == '"' || r == '\'':
+			if !enPalabra {
+				inicio = i
+			}
+			comilla = r
+			enPalabra = true
+		case r == ' ' || r == '\t':
+			if enPalabra {
+				palabras = append(palabras, actual.String())
+				actual.Reset()
+				enPalabra = false
+			}
+			inicio = len(linea)
+		default:
+			if !enPalabra {
+				inicio = i
+			}
+			actual.WriteRune(r)
+			enPalabra = true
+		}
+	}
+	if escapada {
+		actual.WriteRune('\\')
+	}
+	if enPalabra {
+		palabras = append(palabras, actual.String())
+	}
+	return palabras, comilla, inicio
+}
+
+// Citar escribe la palabra de forma que Separar la lea igual: entre comillas dobles si tiene
+// espacios, comillas o está vacía
+func Citar(palabra string) string {
+	if palabra != "" && !strings.ContainsAny(palabra, " \t\"'") {
+		return palabra
+	}
+	reemplazo := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + reemplazo.Replace(palabra) + `"`
+}
@@ -0,0 +1,48 @@
+// Gabriel Seijas 19-00036
+package comandos
+
+import (
+	"slices"
+	"testing"
+)
+
+// Prueba la separación con comillas dobles, simples y barras
+func TestSeparar(t *testing.T) {
+	casos := []struct {
+		linea    string
+		palabras []string
+	}{
+		{"  DEFINIR   PROGRAMA app GO ", []string{"DEFINIR", "PROGRAMA", "app", "GO"}},
+		{`DEFINIR PROGRAMA "mi app" GO`, []string{"DEFINIR", "PROGRAMA", "mi app", "GO"}},
+		{`CODIGO app 'dice "hola"'`, []string{"CODIGO", "app", `dice "hola"`}},
+		{`x "a \"b\" \\ \n"`, []string{"x", `a "b" \ \n`}},
+		{`GUARDAR C:\tmp\cat.json`, []string{"GUARDAR", `C:\tmp\cat.json`}},
+		{`x ""`, []string{"x", ""}},
+		{`pre"fijo y"post`, []string{"prefijo ypost"}},
+		{"", nil},
+	}
+	for _, caso := range casos {
+		palabras, err := Separar(caso.linea)
+		if err != nil || !slices.Equal(palabras, caso.palabras) {
+			t.Errorf("Separar(%q) = %q, %v; se esperaba %q", caso.linea, palabras, err, caso.palabras)
+		}
+	}
+	for _, linea := range []string{`"abierta`, `x 'y`} {
+		if _, err := Separar(linea); err == nil {
+			t.Errorf("Separar(%q) debería fallar por las comillas", linea)
+		}
+	}
+}
+
+// Prueba que Citar produce algo que Separar lee igual
+func TestCitar(t *testing.T) {
+	for _, palabra := range []string{"simple", "con espacio", `con "comillas"`, `barra \ sola`, "", "it's"} {
+		palabras, err := Separar(Citar(palabra))
+		if err != nil || len(palabras) != 1 || palabras[0] != palabra {
+			t.Errorf("Citar(%q) = %s no se lee igual: %q, %v", palabra, Citar(palabra), palabras, err)
+		}
+	}
+	if Citar("simple") != "simple" {
+		t.Errorf("Una palabra simple no necesita comillas")
+	}
+}
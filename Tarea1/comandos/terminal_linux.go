@@ -0,0 +1,37 @@
+// Gabriel Seijas 19-00036
+
+//go:build linux
+
+package comandos
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// modoCrudo pone la terminal en modo carácter por carácter, sin eco ni señales, para que el
+// Lector maneje Tab y Ctrl-C. Devuelve cómo dejarla como estaba, o error si no es una terminal.
+func modoCrudo(archivo *os.File) (func(), error) {
+	fd := archivo.Fd()
+	var original syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, &original); err != nil {
+		return nil, err
+	}
+	crudo := original
+	crudo.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG
+	crudo.Cc[syscall.VMIN] = 1
+	crudo.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, syscall.TCSETS, &crudo); err != nil {
+		return nil, err
+	}
+	return func() { ioctl(fd, syscall.TCSETS, &original) }, nil
+}
+
+// ioctl lee o cambia la configuración de la terminal
+func ioctl(fd uintptr, pedido uintptr, termios *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, pedido, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+// Gabriel Seijas 19-00036
+
+//go:build !linux
+
+package comandos
+
+import (
+	"errors"
+	"os"
+)
+
+// modoCrudo solo está hecho para Linux; en otros sistemas el Lector lee líneas completas y
+// no completa con Tab
+func modoCrudo(archivo *os.File) (func(), error) {
+	return nil, errors.New("modo crudo no disponible en este sistema")
+}
@@ -0,0 +1,122 @@
+// Gabriel Seijas 19-00036
+
+// Package transcripcion prueba un REPL contra archivos con sesiones completas. Cada archivo
+// intercala las líneas que escribe el usuario, que empiezan con "> ", con la salida que se
+// espera después de cada una:
+//
+//	# Lo que está antes de la primera entrada es una descripción y no se compara
+//	> DEFINIR PROGRAMA app Python
+//	Se definió el programa 'app', ejecutable en Python.
+//
+// Con go test -update los archivos se reescriben con la salida obtenida.
+package transcripcion
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var actualizar = flag.Bool("update", false, "reescribe las transcripciones con la salida obtenida")
+
+// Entrada es lo que marca una línea escrita por el usuario
+const Entrada = "> "
+
+// Sesion ejecuta una línea del REPL y devuelve verdadero si la sesión terminó, como con SALIR
+type Sesion func(linea string) bool
+
+// paso es una línea de entrada con la salida que produjo o se espera
+type paso struct {
+	entrada string
+	salida  string
+}
+
+// leer separa un archivo en la descripción y los pasos
+func leer(texto string) (string, []paso) {
+	var descripcion strings.Builder
+	var pasos []paso
+	for _, linea := range strings.SplitAfter(texto, "\n") {
+		switch {
+		case linea == "":
+		case strings.HasPrefix(linea, Entrada):
+			pasos = append(pasos, paso{entrada: strings.TrimSuffix(linea[len(Entrada):], "\n")})
+		case len(pasos) == 0:
+			descripcion.WriteString(linea)
+		default:
+			pasos[len(pasos)-1].salida += linea
+		}
+	}
+	return descripcion.String(), pasos
+}
+
+// escribir arma el archivo a partir de la descripción y los pasos. La salida de cada paso
+// termina siempre en un salto de línea para que la entrada siguiente quede en su línea.
+func escribir(descripcion string, pasos []paso) string {
+	var texto strings.Builder
+	texto.WriteString(descripcion)
+	for _, p := range pasos {
+		texto.WriteString(Entrada + p.entrada + "\n")
+		texto.WriteString(p.salida)
+		if p.salida != "" && !strings.HasSuffix(p.salida, "\n") {
+			texto.WriteString("\n")
+		}
+	}
+	return texto.String()
+}
+
+// correr ejecuta las entradas en una sesión nueva y devuelve los pasos con la salida
+// obtenida. Si la sesión termina antes, las entradas que sobran no se incluyen.
+func correr(esperados []paso, nueva func(salida io.Writer) Sesion) []paso {
+	var salida bytes.Buffer
+	sesion := nueva(&salida)
+	var obtenidos []paso
+	for _, p := range esperados {
+		salida.Reset()
+		termino := sesion(p.entrada)
+		obtenidos = append(obtenidos, paso{entrada: p.entrada, salida: salida.String()})
+		if termino {
+			break
+		}
+	}
+	return obtenidos
+}
+
+// Probar corre cada archivo que coincide con el patrón en una sesión nueva y compara la
+// salida de cada entrada con la esperada. nueva arma la sesión escribiendo en salida.
+func Probar(t *testing.T, patron string, nueva func(salida io.Writer) Sesion) {
+	t.Helper()
+	archivos, err := filepath.Glob(patron)
+	if err != nil || len(archivos) == 0 {
+		t.Fatalf("No hay transcripciones en %s", patron)
+	}
+	for _, archivo := range archivos {
+		t.Run(strings.TrimSuffix(filepath.Base(archivo), filepath.Ext(archivo)), func(t *testing.T) {
+			contenido, err := os.ReadFile(archivo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			descripcion, esperados := leer(string(contenido))
+			obtenidos := correr(esperados, nueva)
+
+			if *actualizar {
+				if err := os.WriteFile(archivo, []byte(escribir(descripcion, obtenidos)), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			for i, p := range obtenidos {
+				esperado := escribir("", esperados[i:i+1])
+				if obtenido := escribir("", []paso{p}); obtenido != esperado {
+					t.Errorf("%s, entrada %d:\nse esperaba:\n%s\nse obtuvo:\n%s", archivo, i+1, esperado, obtenido)
+				}
+			}
+			if len(obtenidos) < len(esperados) {
+				t.Errorf("%s: la sesión terminó en la entrada %d y quedaron %d sin ejecutar", archivo, len(obtenidos), len(esperados)-len(obtenidos))
+			}
+		})
+	}
+}
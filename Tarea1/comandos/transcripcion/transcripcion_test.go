@@ -0,0 +1,46 @@
+// Gabriel Seijas 19-00036
+package transcripcion
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Prueba que leer y escribir conservan el archivo, con la descripción y las salidas vacías
+func TestLeerYEscribir(t *testing.T) {
+	texto := "# Descripción\n\n> uno\nsalida 1\n\n> dos\n> tres\nsalida 3\n"
+	descripcion, pasos := leer(texto)
+	if descripcion != "# Descripción\n\n" {
+		t.Errorf("Descripción incorrecta: %q", descripcion)
+	}
+	esperados := []paso{{"uno", "salida 1\n\n"}, {"dos", ""}, {"tres", "salida 3\n"}}
+	if !slices.Equal(pasos, esperados) {
+		t.Errorf("Pasos incorrectos: %q", pasos)
+	}
+	if escrito := escribir(descripcion, pasos); escrito != texto {
+		t.Errorf("escribir no devuelve el archivo original:\n%s", escrito)
+	}
+}
+
+// Prueba que cada entrada se ejecuta en la misma sesión y que se corta al terminar
+func TestCorrer(t *testing.T) {
+	nueva := func(salida io.Writer) Sesion {
+		vistas := 0
+		return func(linea string) bool {
+			vistas++
+			fmt.Fprintf(salida, "%d %s", vistas, strings.ToUpper(linea))
+			return linea == "fin"
+		}
+	}
+	_, pasos := leer("> a\n> fin\n> b\n")
+	obtenidos := correr(pasos, nueva)
+	if !slices.Equal(obtenidos, []paso{{"a", "1 A"}, {"fin", "2 FIN"}}) {
+		t.Errorf("Pasos obtenidos incorrectos: %q", obtenidos)
+	}
+	if escrito := escribir("", obtenidos); escrito != "> a\n1 A\n> fin\n2 FIN\n" {
+		t.Errorf("La salida sin salto de línea debería terminar en uno: %q", escrito)
+	}
+}
@@ -0,0 +1,48 @@
+// Gabriel Seijas 19-00036
+package main
+
+// Allocator es lo mínimo que debe ofrecer una estrategia de memoria para poder compararla
+type Allocator interface {
+	Reserve(requestedSize int, tag string) error
+	Free(tag string) error
+	UsedMemory() int       // Unidades ocupadas, incluyendo lo que se desperdicia por redondeo
+	FreeMemory() int       // Unidades libres
+	LargestFreeBlock() int // Tamaño del mayor tramo libre contiguo
+}
+
+// segment representa un tramo contiguo de memoria en los allocators basados en listas libres
+type segment struct {
+	address  int
+	size     int
+	free     bool
+	tag      string
+	prev     *segment // Vecino físico anterior
+	next     *segment // Vecino físico siguiente
+	prevFree *segment // Anterior en la lista libre (solo TLSF)
+	nextFree *segment // Siguiente en la lista libre (solo TLSF)
+}
+
+// split deja size unidades en el segmento y devuelve el resto como un segmento libre nuevo.
+// Si no sobra nada devuelve nil.
+func (s *segment) split(size int) *segment {
+	if s.size <= size {
+		return nil
+	}
+	rest := &segment{address: s.address + size, size: s.size - size, free: true, prev: s, next: s.next}
+	if s.next != nil {
+		s.next.prev = rest
+	}
+	s.next = rest
+	s.size = size
+	return rest
+}
+
+// absorbNext une el segmento con su vecino físico siguiente
+func (s *segment) absorbNext() {
+	next := s.next
+	s.size += next.size
+	s.next = next.next
+	if next.next != nil {
+		next.next.prev = s
+	}
+}
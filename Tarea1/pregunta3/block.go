@@ -5,13 +5,17 @@ import "fmt"
 
 // Block representa un bloque de memoria en el sistema buddy
 type Block struct {
-	Size       int    // Tamaño del bloque (debe ser potencia de 2)
-	Address    int    // Dirección inicial del bloque
-	Free       bool   // Indica si el bloque está libre
-	Tag        string // Etiqueta para identificar el bloque si está ocupado
-	Parent     *Block // Referencia al bloque padre
-	LeftChild  *Block // Referencia al hijo izquierdo
-	RightChild *Block // Referencia al hijo derecho
+	Size          int    // Tamaño del bloque (debe ser potencia de 2)
+	Address       int    // Dirección inicial del bloque
+	Free          bool   // Indica si el bloque está libre
+	Tag           string // Etiqueta para identificar el bloque si está ocupado
+	Parent        *Block // Referencia al bloque padre
+	LeftChild     *Block // Referencia al hijo izquierdo
+	RightChild    *Block // Referencia al hijo derecho
+	Requested     int    // Tamaño pedido por el usuario al reservar (sin redondear)
+	Generation    int    // Generación de la reserva actual, sirve para detectar handles obsoletos
+	Alignment     int    // Alineación pedida al reservar (1 si no se pidió)
+	AlignmentCost int    // Unidades extra partidas por la alineación respecto a una reserva normal
 }
 
 // NewBlock crea un bloque nuevo con el tamaño y dirección dados
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 )
 
 // BuddyAllocator maneja la memoria usando el método Buddy System
@@ -13,6 +15,16 @@ type BuddyAllocator struct {
 	FreeLists       [][]*Block        // Listas de bloques libres por nivel
 	AllocatedBlocks map[string]*Block // Bloques reservados identificados por tag
 	RootBlock       *Block            // Bloque raíz que representa toda la memoria
+	Arena           []byte            // Bytes reales de la memoria (nil si no está respaldada)
+	Checked         bool              // Modo verificado: canarios, envenenamiento y generaciones
+	nextGeneration  int               // Contador para asignar generaciones a las reservas
+	freedTags       map[string]Handle // Tags liberados en modo verificado con su último handle
+	Quarantine      []*Block          // Bloques con canarios dañados que no se devuelven a las listas libres
+
+	// Fusión diferida: los bloques liberados esperan en un caché por nivel en vez de fusionarse
+	DeferredCoalescing bool       // Indica si la fusión diferida está activa
+	DeferredCacheLimit int        // Bloques por nivel que se guardan antes de fusionar (0 = ninguno)
+	deferredCache      [][]*Block // Bloques liberados pendientes de fusionar, por nivel
 }
 
 // NewBuddyAllocator inicializa el sistema de memoria con el tamaño dado
@@ -65,6 +77,23 @@ func (ba *BuddyAllocator) removeBlockFromFreeList(block *Block) {
 
 // Reserve reserva un bloque de memoria del tamaño solicitado
 func (ba *BuddyAllocator) Reserve(requestedSize int, tag string) error {
+	return ba.reserve(requestedSize, 1, tag)
+}
+
+// ReserveAligned reserva un bloque cuya dirección sea múltiplo de la alineación dada.
+// La alineación debe ser potencia de 2 y puede ser mayor que el propio bloque.
+func (ba *BuddyAllocator) ReserveAligned(requestedSize, alignment int, tag string) error {
+	if alignment <= 0 || alignment&(alignment-1) != 0 {
+		return errors.New("la alineación debe ser una potencia de 2")
+	}
+	if ba.Checked && alignment > 1 {
+		return errors.New("las reservas alineadas no se pueden usar en modo verificado")
+	}
+	return ba.reserve(requestedSize, alignment, tag)
+}
+
+// reserve busca un bloque libre alineado, lo divide hasta el tamaño necesario y lo marca ocupado
+func (ba *BuddyAllocator) reserve(requestedSize, alignment int, tag string) error {
 	if requestedSize <= 0 {
 		return errors.New("el tamaño solicitado debe ser positivo")
 	}
@@ -72,26 +101,33 @@ func (ba *BuddyAllocator) Reserve(requestedSize int, tag string) error {
 		return errors.New("ya existe un bloque con ese nombre")
 	}
 
+	// En modo verificado se deja espacio para los canarios alrededor de la reserva
+	neededSize := requestedSize
+	if ba.Checked {
+		neededSize += 2 * CanarySize
+	}
+
 	// Busca el tamaño real (potencia de 2) que cubre la solicitud
 	actualSize := 1
-	for actualSize < requestedSize {
+	for actualSize < neededSize {
 		actualSize *= 2
 	}
 
 	targetLevel := int(math.Log2(float64(actualSize)))
 
-	var foundBlock *Block
-	for level := targetLevel; level < len(ba.FreeLists); level++ {
-		if len(ba.FreeLists[level]) > 0 {
-			foundBlock = ba.FreeLists[level][0]
-			ba.removeBlockFromFreeList(foundBlock)
-			break
-		}
+	foundBlock, plainSize := ba.findFreeBlock(targetLevel, alignment)
+	if foundBlock == nil && ba.pendingCoalesce() > 0 {
+		// Con fusión diferida puede que la memoria solo esté partida: se fusiona y se reintenta
+		ba.Coalesce()
+		foundBlock, plainSize = ba.findFreeBlock(targetLevel, alignment)
 	}
 
 	if foundBlock == nil {
 		return errors.New("no hay suficiente memoria disponible para la solicitud")
 	}
+	ba.removeBlockFromFreeList(foundBlock)
+	ba.dropFromDeferredCache(foundBlock)
+	alignmentCost := foundBlock.Size - plainSize
 
 	// Divide el bloque hasta llegar al tamaño necesario
 	for foundBlock.Size > actualSize {
@@ -100,29 +136,191 @@ func (ba *BuddyAllocator) Reserve(requestedSize int, tag string) error {
 		foundBlock = leftChild
 	}
 
-	foundBlock.Free = false
-	foundBlock.Tag = tag
-	ba.AllocatedBlocks[tag] = foundBlock
+	if ba.Checked {
+		if err := ba.checkPoison(foundBlock, tag); err != nil {
+			// Se devuelve el bloque por el mismo camino que al liberar para no perderlo
+			ba.release(foundBlock)
+			return err
+		}
+		ba.writeCanaries(foundBlock, requestedSize)
+		delete(ba.freedTags, tag)
+	}
+
+	ba.markReserved(foundBlock, requestedSize, tag)
+	foundBlock.Alignment = alignment
+	foundBlock.AlignmentCost = alignmentCost
 	return nil
 }
 
+// findFreeBlock busca el primer bloque libre alineado desde el nivel pedido hacia arriba.
+// También devuelve el tamaño del bloque que se hubiera usado sin pedir alineación.
+func (ba *BuddyAllocator) findFreeBlock(targetLevel, alignment int) (*Block, int) {
+	// Con fusión diferida se prefiere el último bloque liberado de ese mismo nivel
+	if cached := ba.cachedBlock(targetLevel, alignment); cached != nil {
+		return cached, cached.Size
+	}
+
+	plainSize := 0
+	for level := targetLevel; level < len(ba.FreeLists); level++ {
+		for _, block := range ba.FreeLists[level] {
+			if plainSize == 0 {
+				plainSize = block.Size
+			}
+			// Los bloques de tamaño >= alineación siempre están alineados, y al dividir
+			// se conserva la dirección del hijo izquierdo
+			if block.Address%alignment == 0 {
+				return block, plainSize
+			}
+		}
+	}
+	return nil, plainSize
+}
+
+// markReserved marca el bloque como ocupado con una generación nueva
+func (ba *BuddyAllocator) markReserved(block *Block, requestedSize int, tag string) {
+	ba.nextGeneration++
+	block.Free = false
+	block.Tag = tag
+	block.Requested = requestedSize
+	block.Generation = ba.nextGeneration
+	block.Alignment = 1
+	block.AlignmentCost = 0
+	ba.AllocatedBlocks[tag] = block
+}
+
+// ReserveAt reserva exactamente el rango que empieza en la dirección dada, dividiendo el
+// árbol hasta llegar a él. El rango debe coincidir con un bloque buddy: la dirección tiene
+// que ser múltiplo del tamaño redondeado a potencia de 2.
+func (ba *BuddyAllocator) ReserveAt(address, requestedSize int, tag string) error {
+	if requestedSize <= 0 {
+		return errors.New("el tamaño solicitado debe ser positivo")
+	}
+	if _, exists := ba.AllocatedBlocks[tag]; exists {
+		return errors.New("ya existe un bloque con ese nombre")
+	}
+	if ba.Checked {
+		return errors.New("las reservas en dirección fija no se pueden usar en modo verificado")
+	}
+
+	actualSize := 1
+	for actualSize < requestedSize {
+		actualSize *= 2
+	}
+	if address < 0 || address+actualSize > ba.TotalMemorySize {
+		return fmt.Errorf("el rango [%d, %d) se sale de la memoria", address, address+actualSize)
+	}
+	if address%actualSize != 0 {
+		return fmt.Errorf("la dirección %d no está alineada a %d, el tamaño del bloque buddy", address, actualSize)
+	}
+
+	// Los bloques pendientes de fusionar harían que el rango libre aparezca partido
+	ba.Coalesce()
+
+	// Primero se revisa sin tocar nada, así un fallo no deja bloques divididos
+	if conflicts := ba.conflictsAt(address, actualSize); len(conflicts) > 0 {
+		return fmt.Errorf("el rango [%d, %d) está ocupado por: %s", address, address+actualSize, strings.Join(conflicts, ", "))
+	}
+
+	block := ba.RootBlock
+	for block.Size > actualSize {
+		if block.LeftChild == nil {
+			ba.removeBlockFromFreeList(block)
+			leftChild, rightChild := block.Split()
+			ba.addBlockToFreeList(leftChild)
+			ba.addBlockToFreeList(rightChild)
+		}
+		if address < block.RightChild.Address {
+			block = block.LeftChild
+		} else {
+			block = block.RightChild
+		}
+	}
+
+	ba.removeBlockFromFreeList(block)
+	ba.markReserved(block, requestedSize, tag)
+	return nil
+}
+
+// conflictsAt devuelve, ordenados, los tags de las reservas que se cruzan con el rango
+func (ba *BuddyAllocator) conflictsAt(address, size int) []string {
+	block := ba.RootBlock
+	for block.Size > size {
+		if block.LeftChild == nil {
+			if !block.Free {
+				return []string{block.Tag}
+			}
+			return nil
+		}
+		if address < block.RightChild.Address {
+			block = block.LeftChild
+		} else {
+			block = block.RightChild
+		}
+	}
+
+	var tags []string
+	var collect func(b *Block)
+	collect = func(b *Block) {
+		if b.LeftChild == nil {
+			if !b.Free {
+				tags = append(tags, b.Tag)
+			}
+			return
+		}
+		collect(b.LeftChild)
+		collect(b.RightChild)
+	}
+	collect(block)
+	sort.Strings(tags)
+	return tags
+}
+
 // Free libera un bloque de memoria previamente reservado
 func (ba *BuddyAllocator) Free(tag string) error {
 	blockToFree, exists := ba.AllocatedBlocks[tag]
 	if !exists {
+		if freed, wasFreed := ba.freedTags[tag]; ba.Checked && wasFreed {
+			return &CorruptionError{Tag: tag, Address: freed.Address, Operation: "LIBERAR",
+				Detail: fmt.Sprintf("doble liberación (generación %d)", freed.Generation)}
+		}
 		return errors.New("no existe un bloque con ese nombre")
 	}
 
+	if ba.Checked {
+		ba.freedTags[tag] = Handle{Tag: tag, Address: blockToFree.Address, Generation: blockToFree.Generation}
+		if err := ba.checkCanaries(blockToFree, "LIBERAR"); err != nil {
+			// No se puede confiar en lo que rodea al bloque, así que no se reutiliza: queda en
+			// cuarentena, fuera de las reservas, y Stats lo cuenta aparte
+			delete(ba.AllocatedBlocks, tag)
+			ba.Quarantine = append(ba.Quarantine, blockToFree)
+			return err
+		}
+		ba.poison(blockToFree)
+	}
+
 	blockToFree.Free = true
 	blockToFree.Tag = ""
+	blockToFree.Requested = 0
+	blockToFree.Alignment = 0
+	blockToFree.AlignmentCost = 0
 	delete(ba.AllocatedBlocks, tag)
 
-	ba.addBlockToFreeList(blockToFree)
+	ba.release(blockToFree)
+	return nil
+}
 
-	// Intenta fusionar el bloque con su buddy si ambos están libres
-	ba.coalesce(blockToFree)
+// release pone un bloque libre en su lista y lo fusiona con su buddy, o lo deja en el caché
+// si la fusión es diferida
+func (ba *BuddyAllocator) release(block *Block) {
+	ba.addBlockToFreeList(block)
 
-	return nil
+	if ba.DeferredCoalescing {
+		ba.deferCoalesce(block)
+		return
+	}
+
+	// Intenta fusionar el bloque con su buddy si ambos están libres
+	ba.coalesce(block)
 }
 
 // coalesce fusiona un bloque con su buddy si ambos están libres, de forma recursiva
@@ -195,3 +393,97 @@ func (ba *BuddyAllocator) displayBlock(block *Block, indent int) {
 func (ba *BuddyAllocator) GetAllocatedBlocks() map[string]*Block {
 	return ba.AllocatedBlocks
 }
+
+// UsedMemory devuelve cuántas unidades están ocupadas (contando el redondeo a potencia de 2)
+func (ba *BuddyAllocator) UsedMemory() int {
+	used := 0
+	for _, block := range ba.AllocatedBlocks {
+		used += block.Size
+	}
+	return used
+}
+
+// FreeMemory devuelve cuántas unidades quedan libres; los bloques en cuarentena no cuentan
+func (ba *BuddyAllocator) FreeMemory() int {
+	return ba.TotalMemorySize - ba.UsedMemory() - ba.quarantinedMemory()
+}
+
+// quarantinedMemory devuelve cuántas unidades ocupan los bloques en cuarentena
+func (ba *BuddyAllocator) quarantinedMemory() int {
+	quarantined := 0
+	for _, block := range ba.Quarantine {
+		quarantined += block.Size
+	}
+	return quarantined
+}
+
+// LargestFreeBlock devuelve el tamaño del bloque libre más grande
+func (ba *BuddyAllocator) LargestFreeBlock() int {
+	for level := len(ba.FreeLists) - 1; level >= 0; level-- {
+		if len(ba.FreeLists[level]) > 0 {
+			return ba.FreeLists[level][0].Size
+		}
+	}
+	return 0
+}
+
+// AllocatorStats resume el uso de la memoria y de dónde sale el desperdicio
+type AllocatorStats struct {
+	TotalMemory      int // Tamaño total de la memoria
+	UsedMemory       int // Unidades en bloques ocupados
+	RequestedMemory  int // Unidades que pidieron los usuarios
+	FreeMemory       int // Unidades libres
+	LargestFreeBlock int // Mayor bloque libre
+	AllocatedBlocks  int // Cantidad de reservas vivas
+	RoundingWaste    int // Desperdicio interno: redondeo a potencia de 2 (y canarios en modo verificado)
+	AlignedBlocks    int // Reservas vivas que pidieron alineación mayor a 1
+	AlignmentCost    int // Unidades de bloques más grandes que se partieron solo por la alineación
+	PendingCoalesce  int // Bloques libres que esperan fusión en modo diferido
+	Quarantined      int // Bloques en cuarentena por canarios dañados en modo verificado
+	QuarantineMemory int // Unidades que ocupan esos bloques, que ya no están libres ni reservadas
+}
+
+// Stats calcula las estadísticas actuales de la memoria
+func (ba *BuddyAllocator) Stats() AllocatorStats {
+	stats := AllocatorStats{
+		TotalMemory:      ba.TotalMemorySize,
+		LargestFreeBlock: ba.LargestFreeBlock(),
+		AllocatedBlocks:  len(ba.AllocatedBlocks),
+		PendingCoalesce:  ba.pendingCoalesce(),
+		Quarantined:      len(ba.Quarantine),
+		QuarantineMemory: ba.quarantinedMemory(),
+	}
+	for _, block := range ba.AllocatedBlocks {
+		stats.UsedMemory += block.Size
+		stats.RequestedMemory += block.Requested
+		if block.Alignment > 1 {
+			stats.AlignedBlocks++
+			stats.AlignmentCost += block.AlignmentCost
+		}
+	}
+	stats.FreeMemory = stats.TotalMemory - stats.UsedMemory - stats.QuarantineMemory
+	stats.RoundingWaste = stats.UsedMemory - stats.RequestedMemory
+	return stats
+}
+
+// ShowStats muestra las estadísticas y explica el desperdicio
+func (ba *BuddyAllocator) ShowStats() {
+	stats := ba.Stats()
+	fmt.Println("\n Estadísticas de la Memoria ")
+	fmt.Printf("Total: %d, Ocupada: %d, Pedida: %d, Libre: %d\n", stats.TotalMemory, stats.UsedMemory, stats.RequestedMemory, stats.FreeMemory)
+	fmt.Printf("Reservas: %d, Mayor bloque libre: %d\n", stats.AllocatedBlocks, stats.LargestFreeBlock)
+	fmt.Printf("Desperdicio por redondeo: %d unidades\n", stats.RoundingWaste)
+	fmt.Printf("Reservas alineadas: %d, bloques partidos de más por alineación: %d unidades\n", stats.AlignedBlocks, stats.AlignmentCost)
+	if ba.DeferredCoalescing {
+		fmt.Printf("Bloques pendientes de fusionar: %d\n", stats.PendingCoalesce)
+	}
+	if stats.Quarantined > 0 {
+		fmt.Printf("Bloques en cuarentena por corrupción: %d (%d unidades)\n", stats.Quarantined, stats.QuarantineMemory)
+	}
+	if stats.AlignmentCost > 0 {
+		// En el buddy system un bloque alineado no ocupa más que uno normal: el costo es que
+		// se parte un bloque más grande y la memoria libre queda más fragmentada
+		fmt.Println("(la alineación no agrega desperdicio interno, pero fragmenta bloques libres más grandes)")
+	}
+	fmt.Println("---------------------------")
+}
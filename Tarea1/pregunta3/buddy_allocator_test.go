@@ -352,3 +352,264 @@ func TestDisplayBlockNilAndFreeState(t *testing.T) {
 		t.Errorf("displayBlock no muestra estado OCUPADO")
 	}
 }
+
+// Test para reservas alineadas más allá del tamaño del bloque
+func TestReserveAligned(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(128)
+	_ = allocator.Reserve(4, "a") // queda en la dirección 0
+
+	// Un buffer de 4 alineado a 64 no puede usar los bloques libres de 4, 8, 16 y 32
+	err := allocator.ReserveAligned(4, 64, "buf")
+	if err != nil {
+		t.Fatalf("No se pudo reservar alineado: %v", err)
+	}
+	block := allocator.AllocatedBlocks["buf"]
+	if block.Address%64 != 0 || block.Size != 4 {
+		t.Errorf("Bloque alineado incorrecto: %+v", block)
+	}
+	if block.AlignmentCost != 60 { // se partió uno de 64 en vez del libre de 4
+		t.Errorf("Costo de alineación incorrecto: %d", block.AlignmentCost)
+	}
+
+	// Los libres de 4 están en 4 y 68, así que para alinear a 8 se parte el de 8 en la dirección 8
+	_ = allocator.ReserveAligned(4, 8, "b")
+	if allocator.AllocatedBlocks["b"].Address != 8 || allocator.AllocatedBlocks["b"].AlignmentCost != 4 {
+		t.Errorf("Bloque alineado a 8 incorrecto: %+v", allocator.AllocatedBlocks["b"])
+	}
+
+	// Si ya hay un bloque alineado del tamaño justo se usa sin costo
+	_ = allocator.ReserveAligned(4, 4, "c")
+	if allocator.AllocatedBlocks["c"].Address != 4 || allocator.AllocatedBlocks["c"].AlignmentCost != 0 {
+		t.Errorf("Debería usar el bloque ya alineado: %+v", allocator.AllocatedBlocks["c"])
+	}
+
+	if err := allocator.ReserveAligned(4, 3, "d"); err == nil {
+		t.Errorf("Debería fallar con alineación que no es potencia de 2")
+	}
+	if err := allocator.ReserveAligned(4, 256, "d"); err == nil || !strings.Contains(err.Error(), "no hay suficiente memoria") {
+		t.Errorf("Debería fallar con alineación imposible: %v", err)
+	}
+
+	checked, _ := NewBuddyAllocator(64)
+	_ = checked.EnableCheckedMode()
+	if err := checked.ReserveAligned(4, 16, "x"); err == nil {
+		t.Errorf("Las reservas alineadas no deberían permitirse en modo verificado")
+	}
+}
+
+// Test para las estadísticas de la memoria
+func TestStats(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(128)
+	_ = allocator.Reserve(3, "a")
+	_ = allocator.ReserveAligned(4, 64, "buf")
+
+	stats := allocator.Stats()
+	if stats.UsedMemory != 8 || stats.RequestedMemory != 7 || stats.RoundingWaste != 1 {
+		t.Errorf("Uso incorrecto: %+v", stats)
+	}
+	if stats.FreeMemory != 120 || stats.AllocatedBlocks != 2 || stats.LargestFreeBlock != 32 {
+		t.Errorf("Memoria libre incorrecta: %+v", stats)
+	}
+	if stats.AlignedBlocks != 1 || stats.AlignmentCost != 60 {
+		t.Errorf("Estadísticas de alineación incorrectas: %+v", stats)
+	}
+
+	output := captureOutput(func() {
+		allocator.ShowStats()
+	})
+	if !strings.Contains(output, "Desperdicio por redondeo: 1 unidades") || !strings.Contains(output, "fragmenta") {
+		t.Errorf("ShowStats no explica el desperdicio: %s", output)
+	}
+}
+
+// Test para reservar un rango fijo de memoria
+func TestReserveAt(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(64)
+
+	if err := allocator.ReserveAt(48, 8, "dispositivo"); err != nil {
+		t.Fatalf("No se pudo reservar en la dirección 48: %v", err)
+	}
+	block := allocator.AllocatedBlocks["dispositivo"]
+	if block.Address != 48 || block.Size != 8 {
+		t.Errorf("Bloque fijo incorrecto: %+v", block)
+	}
+
+	// Las reservas normales usan lo que quedó libre alrededor
+	_ = allocator.Reserve(32, "grande")
+	if allocator.AllocatedBlocks["grande"].Address != 0 {
+		t.Errorf("La reserva normal debería quedar en 0")
+	}
+	if allocator.Stats().FreeMemory != 24 {
+		t.Errorf("Memoria libre incorrecta: %d", allocator.Stats().FreeMemory)
+	}
+
+	// Se libera como cualquier otro bloque y todo se vuelve a fusionar
+	_ = allocator.Free("dispositivo")
+	_ = allocator.Free("grande")
+	if len(allocator.FreeLists[6]) != 1 {
+		t.Errorf("El bloque raíz no se fusionó después de liberar el bloque fijo")
+	}
+}
+
+// Test para los errores de ReserveAt
+func TestReserveAtErrors(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(64)
+	_ = allocator.ReserveAt(0, 4, "a")
+	_ = allocator.ReserveAt(8, 2, "b")
+
+	err := allocator.ReserveAt(0, 16, "c")
+	if err == nil || !strings.Contains(err.Error(), "ocupado por: a, b") {
+		t.Errorf("No describió los tags en conflicto: %v", err)
+	}
+	err = allocator.ReserveAt(2, 2, "c")
+	if err == nil || !strings.Contains(err.Error(), "ocupado por: a") {
+		t.Errorf("No detectó el conflicto dentro de un bloque ocupado: %v", err)
+	}
+	if err := allocator.ReserveAt(4, 8, "c"); err == nil || !strings.Contains(err.Error(), "no está alineada") {
+		t.Errorf("No detectó la dirección desalineada: %v", err)
+	}
+	if err := allocator.ReserveAt(64, 1, "c"); err == nil || !strings.Contains(err.Error(), "se sale de la memoria") {
+		t.Errorf("No detectó el rango fuera de la memoria: %v", err)
+	}
+	if err := allocator.ReserveAt(16, 0, "c"); err == nil {
+		t.Errorf("No dio error con tamaño cero")
+	}
+	if err := allocator.ReserveAt(16, 4, "a"); err == nil || !strings.Contains(err.Error(), "ya existe un bloque") {
+		t.Errorf("No detectó nombre duplicado")
+	}
+
+	// Un fallo no debe dejar bloques divididos de más
+	fresh, _ := NewBuddyAllocator(16)
+	_ = fresh.Reserve(16, "todo")
+	_ = fresh.ReserveAt(4, 4, "x")
+	if fresh.RootBlock.LeftChild != nil {
+		t.Errorf("ReserveAt dividió bloques a pesar de fallar")
+	}
+
+	checked, _ := NewBuddyAllocator(64)
+	_ = checked.EnableCheckedMode()
+	if err := checked.ReserveAt(0, 4, "x"); err == nil {
+		t.Errorf("ReserveAt no debería permitirse en modo verificado")
+	}
+}
+
+// Test para la fusión diferida: los bloques liberados se reutilizan sin volver a dividir
+func TestDeferredCoalescing(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(16)
+	allocator.SetDeferredCoalescing(true, 8)
+
+	_ = allocator.Reserve(1, "a")
+	firstBlock := allocator.AllocatedBlocks["a"]
+	_ = allocator.Free("a")
+	if allocator.Stats().PendingCoalesce != 1 || len(allocator.FreeLists[4]) != 0 {
+		t.Errorf("El bloque liberado no debería fusionarse todavía")
+	}
+
+	// La misma reserva reutiliza el bloque guardado en su nivel
+	_ = allocator.Reserve(1, "b")
+	if allocator.AllocatedBlocks["b"] != firstBlock || allocator.Stats().PendingCoalesce != 0 {
+		t.Errorf("No se reutilizó el bloque del caché")
+	}
+
+	_ = allocator.Free("b")
+	allocator.Coalesce()
+	if len(allocator.FreeLists[4]) != 1 || allocator.Stats().PendingCoalesce != 0 {
+		t.Errorf("Coalesce no restauró el bloque raíz")
+	}
+}
+
+// Test para la fusión diferida bajo presión y con límite de caché
+func TestDeferredCoalescingPressureAndLimit(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(16)
+	allocator.SetDeferredCoalescing(true, 8)
+	for _, tag := range []string{"a", "b", "c", "d"} {
+		_ = allocator.Reserve(4, tag)
+	}
+	for _, tag := range []string{"a", "b", "c", "d"} {
+		_ = allocator.Free(tag)
+	}
+
+	// Sin fusionar no hay bloque de 16, pero la falta de memoria dispara la fusión
+	if err := allocator.Reserve(16, "todo"); err != nil {
+		t.Errorf("La reserva debería fusionar los pendientes y funcionar: %v", err)
+	}
+
+	limited, _ := NewBuddyAllocator(16)
+	limited.SetDeferredCoalescing(true, 1)
+	_ = limited.Reserve(4, "a")
+	_ = limited.Reserve(4, "b")
+	_ = limited.Free("a")
+	_ = limited.Free("b") // el caché de nivel 2 se pasa del límite y se fusiona 'a' con 'b'
+	if limited.Stats().PendingCoalesce != 0 || len(limited.FreeLists[4]) != 1 {
+		t.Errorf("El límite del caché no forzó la fusión: %+v", limited.Stats())
+	}
+
+	// Al desactivar la fusión diferida se fusiona todo
+	limited.SetDeferredCoalescing(false, 0)
+	if limited.Stats().PendingCoalesce != 0 {
+		t.Errorf("Desactivar la fusión diferida debería vaciar el caché")
+	}
+	_ = limited.Reserve(1, "x")
+	_ = limited.Free("x")
+	if len(limited.FreeLists[4]) != 1 {
+		t.Errorf("Con la fusión normal el bloque raíz debería restaurarse al liberar")
+	}
+}
+
+// Test para el límite 0, que no guarda nada, y para que solo cuenten los bloques que se pueden fusionar
+func TestDeferredCoalescingNoCacheAndPending(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(16)
+	allocator.SetDeferredCoalescing(true, 0)
+	for i := 0; i < 100; i++ {
+		_ = allocator.Reserve(1, "a")
+		_ = allocator.Free("a")
+	}
+	if allocator.pendingCoalesce() != 0 || len(allocator.deferredCache[0]) != 0 || len(allocator.FreeLists[4]) != 1 {
+		t.Errorf("Con límite 0 cada bloque debería fusionarse al liberarse")
+	}
+
+	cached, _ := NewBuddyAllocator(16)
+	cached.SetDeferredCoalescing(true, 8)
+	_ = cached.Reserve(1, "a")
+	_ = cached.Reserve(1, "b")
+	_ = cached.Free("a")
+	if cached.Stats().PendingCoalesce != 0 {
+		t.Errorf("Un bloque con el buddy ocupado no tiene nada que fusionar")
+	}
+	_ = cached.Free("b")
+	if cached.Stats().PendingCoalesce != 2 {
+		t.Errorf("Los dos buddies libres deberían contar como pendientes: %d", cached.Stats().PendingCoalesce)
+	}
+}
+
+// Test para ReserveAt cuando el rango está libre pero sin fusionar
+func TestReserveAtWithDeferredCoalescing(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(16)
+	allocator.SetDeferredCoalescing(true, 8)
+	_ = allocator.Reserve(2, "a")
+	_ = allocator.Free("a")
+	if err := allocator.ReserveAt(0, 8, "dev"); err != nil {
+		t.Fatalf("No se pudo reservar el rango libre: %v", err)
+	}
+	if block := allocator.AllocatedBlocks["dev"]; block.LeftChild != nil || block.Size != 8 {
+		t.Errorf("El bloque fijo quedó con hijos: %+v", block)
+	}
+}
+
+// Benchmark de reservar y liberar el mismo tamaño una y otra vez, con fusión normal y diferida
+func BenchmarkPingPong(b *testing.B) {
+	for _, deferred := range []bool{false, true} {
+		name := "normal"
+		if deferred {
+			name = "diferida"
+		}
+		b.Run(name, func(b *testing.B) {
+			allocator, _ := NewBuddyAllocator(1 << 16)
+			allocator.SetDeferredCoalescing(deferred, 8)
+			for i := 0; i < b.N; i++ {
+				_ = allocator.Reserve(1, "x")
+				_ = allocator.Free("x")
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Constantes del modo verificado
+const (
+	CanarySize = 4    // Cantidad de bytes de canario a cada lado de una reserva
+	CanaryByte = 0xAB // Valor que se escribe en los canarios
+	PoisonByte = 0xDD // Valor con el que se envenenan los bloques libres
+)
+
+// Handle identifica una reserva concreta: el tag, dónde quedó y en qué generación
+type Handle struct {
+	Tag        string
+	Address    int
+	Generation int
+}
+
+// CorruptionError describe una corrupción detectada en modo verificado
+type CorruptionError struct {
+	Tag       string // Bloque involucrado
+	Address   int    // Dirección donde se detectó el problema
+	Operation string // Operación que lo detectó (RESERVAR, LIBERAR, VERIFICAR)
+	Detail    string // Qué se encontró
+}
+
+// Error devuelve el mensaje de la corrupción
+func (e *CorruptionError) Error() string {
+	return fmt.Sprintf("corrupción detectada en %s de '%s' (dirección %d): %s", e.Operation, e.Tag, e.Address, e.Detail)
+}
+
+// EnableCheckedMode respalda la memoria con bytes reales y activa las verificaciones.
+// Solo se puede activar mientras no haya bloques reservados.
+func (ba *BuddyAllocator) EnableCheckedMode() error {
+	if len(ba.AllocatedBlocks) > 0 {
+		return errors.New("el modo verificado solo se puede activar sin bloques reservados")
+	}
+	if ba.Arena == nil {
+		ba.Arena = make([]byte, ba.TotalMemorySize)
+	}
+	for i := range ba.Arena {
+		ba.Arena[i] = PoisonByte
+	}
+	ba.Checked = true
+	ba.freedTags = make(map[string]Handle)
+	return nil
+}
+
+// HandleOf devuelve el handle de la reserva actual con ese tag
+func (ba *BuddyAllocator) HandleOf(tag string) (Handle, error) {
+	block, exists := ba.AllocatedBlocks[tag]
+	if !exists {
+		return Handle{}, errors.New("no existe un bloque con ese nombre")
+	}
+	return Handle{Tag: tag, Address: block.Address, Generation: block.Generation}, nil
+}
+
+// FreeHandle libera la reserva del handle solo si sigue siendo la misma reserva
+func (ba *BuddyAllocator) FreeHandle(h Handle) error {
+	block, exists := ba.AllocatedBlocks[h.Tag]
+	if exists && block.Address == h.Address && block.Generation == h.Generation {
+		return ba.Free(h.Tag)
+	}
+	if freed, wasFreed := ba.freedTags[h.Tag]; wasFreed && freed == h {
+		return &CorruptionError{Tag: h.Tag, Address: h.Address, Operation: "LIBERAR",
+			Detail: fmt.Sprintf("doble liberación (generación %d)", h.Generation)}
+	}
+	return &CorruptionError{Tag: h.Tag, Address: h.Address, Operation: "LIBERAR",
+		Detail: fmt.Sprintf("handle obsoleto (generación %d)", h.Generation)}
+}
+
+// payloadAddress devuelve dónde empiezan los datos del usuario dentro del bloque
+func (ba *BuddyAllocator) payloadAddress(block *Block) int {
+	if ba.Checked {
+		return block.Address + CanarySize
+	}
+	return block.Address
+}
+
+// Write escribe datos en la reserva a partir del desplazamiento dado.
+// Igual que en C, solo se valida que no se salga de la memoria, no de la reserva,
+// así que un desbordamiento pisa los canarios y se detecta después.
+func (ba *BuddyAllocator) Write(tag string, offset int, data []byte) error {
+	if ba.Arena == nil {
+		return errors.New("la memoria no está respaldada por bytes")
+	}
+	block, exists := ba.AllocatedBlocks[tag]
+	if !exists {
+		return errors.New("no existe un bloque con ese nombre")
+	}
+	start := ba.payloadAddress(block) + offset
+	if start < 0 || start+len(data) > len(ba.Arena) {
+		return errors.New("la escritura se sale de la memoria")
+	}
+	copy(ba.Arena[start:], data)
+	return nil
+}
+
+// Read devuelve una copia de los datos de la reserva
+func (ba *BuddyAllocator) Read(tag string) ([]byte, error) {
+	if ba.Arena == nil {
+		return nil, errors.New("la memoria no está respaldada por bytes")
+	}
+	block, exists := ba.AllocatedBlocks[tag]
+	if !exists {
+		return nil, errors.New("no existe un bloque con ese nombre")
+	}
+	start := ba.payloadAddress(block)
+	data := make([]byte, block.Requested)
+	copy(data, ba.Arena[start:start+block.Requested])
+	return data, nil
+}
+
+// CheckIntegrity revisa los canarios de todas las reservas y el veneno de los bloques libres
+func (ba *BuddyAllocator) CheckIntegrity() error {
+	if !ba.Checked {
+		return errors.New("el modo verificado no está activo")
+	}
+
+	// Se recorren en orden de dirección para que el reporte sea siempre el mismo
+	blocks := make([]*Block, 0, len(ba.AllocatedBlocks))
+	for _, block := range ba.AllocatedBlocks {
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Address < blocks[j].Address })
+	for _, block := range blocks {
+		if err := ba.checkCanaries(block, "VERIFICAR"); err != nil {
+			return err
+		}
+	}
+
+	for _, list := range ba.FreeLists {
+		for _, block := range list {
+			if addr, ok := ba.findUnpoisoned(block); !ok {
+				return &CorruptionError{Tag: "", Address: addr, Operation: "VERIFICAR",
+					Detail: "bloque libre modificado (uso después de liberar)"}
+			}
+		}
+	}
+	return nil
+}
+
+// writeCanaries escribe los canarios antes y después de los datos pedidos
+func (ba *BuddyAllocator) writeCanaries(block *Block, requestedSize int) {
+	back := block.Address + CanarySize + requestedSize
+	for i := 0; i < CanarySize; i++ {
+		ba.Arena[block.Address+i] = CanaryByte
+		ba.Arena[back+i] = CanaryByte
+	}
+}
+
+// checkCanaries confirma que los canarios de una reserva siguen intactos
+func (ba *BuddyAllocator) checkCanaries(block *Block, operation string) error {
+	back := block.Address + CanarySize + block.Requested
+	for i := 0; i < CanarySize; i++ {
+		if ba.Arena[block.Address+i] != CanaryByte {
+			return &CorruptionError{Tag: block.Tag, Address: block.Address + i, Operation: operation,
+				Detail: "canario delantero dañado (escritura antes del inicio)"}
+		}
+		if ba.Arena[back+i] != CanaryByte {
+			return &CorruptionError{Tag: block.Tag, Address: back + i, Operation: operation,
+				Detail: "canario trasero dañado (desbordamiento)"}
+		}
+	}
+	return nil
+}
+
+// poison llena el bloque con el valor de veneno
+func (ba *BuddyAllocator) poison(block *Block) {
+	for i := block.Address; i < block.Address+block.Size; i++ {
+		ba.Arena[i] = PoisonByte
+	}
+}
+
+// findUnpoisoned busca el primer byte del bloque que no tenga veneno
+func (ba *BuddyAllocator) findUnpoisoned(block *Block) (int, bool) {
+	for i := block.Address; i < block.Address+block.Size; i++ {
+		if ba.Arena[i] != PoisonByte {
+			return i, false
+		}
+	}
+	return 0, true
+}
+
+// checkPoison confirma que nadie escribió en el bloque mientras estaba libre.
+// Si lo encuentra modificado lo vuelve a envenenar para reportarlo una sola vez.
+func (ba *BuddyAllocator) checkPoison(block *Block, tag string) error {
+	if addr, ok := ba.findUnpoisoned(block); !ok {
+		ba.poison(block)
+		return &CorruptionError{Tag: tag, Address: addr, Operation: "RESERVAR",
+			Detail: "bloque libre modificado (uso después de liberar)"}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Test para activar el modo verificado y ver que la memoria queda envenenada
+func TestEnableCheckedMode(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(64)
+	if err := allocator.EnableCheckedMode(); err != nil {
+		t.Fatalf("No se pudo activar el modo verificado: %v", err)
+	}
+	if len(allocator.Arena) != 64 || allocator.Arena[10] != PoisonByte {
+		t.Errorf("La memoria no quedó respaldada y envenenada")
+	}
+
+	// No se puede activar si ya hay reservas
+	allocatorBusy, _ := NewBuddyAllocator(64)
+	_ = allocatorBusy.Reserve(4, "p1")
+	if err := allocatorBusy.EnableCheckedMode(); err == nil {
+		t.Errorf("Debería fallar al activar el modo verificado con bloques reservados")
+	}
+}
+
+// Test para checar que las reservas dejan espacio para los canarios
+func TestCheckedReserveWithCanaries(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(64)
+	_ = allocator.EnableCheckedMode()
+
+	if err := allocator.Reserve(4, "buf"); err != nil {
+		t.Fatalf("No se pudo reservar en modo verificado: %v", err)
+	}
+	block := allocator.AllocatedBlocks["buf"]
+	if block.Size != 16 { // 4 + 2*4 canarios = 12 -> 16
+		t.Errorf("El tamaño no incluye los canarios, obtuve %d", block.Size)
+	}
+
+	if err := allocator.Write("buf", 0, []byte("hola")); err != nil {
+		t.Fatalf("No se pudo escribir: %v", err)
+	}
+	data, _ := allocator.Read("buf")
+	if string(data) != "hola" {
+		t.Errorf("Read devolvió '%s'", string(data))
+	}
+	if err := allocator.CheckIntegrity(); err != nil {
+		t.Errorf("No debería haber corrupción: %v", err)
+	}
+	if err := allocator.Free("buf"); err != nil {
+		t.Errorf("No se pudo liberar buf: %v", err)
+	}
+}
+
+// Test para detectar un desbordamiento que pisa el canario trasero
+func TestCheckedOverflowDetected(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(64)
+	_ = allocator.EnableCheckedMode()
+	_ = allocator.Reserve(4, "buf")
+
+	_ = allocator.Write("buf", 0, []byte("hola!")) // un byte de más
+	err := allocator.Free("buf")
+	var corruption *CorruptionError
+	if !errors.As(err, &corruption) {
+		t.Fatalf("Esperaba un CorruptionError, obtuve %v", err)
+	}
+	if corruption.Tag != "buf" || corruption.Operation != "LIBERAR" || corruption.Address != CanarySize+4 {
+		t.Errorf("Reporte de corrupción incorrecto: %+v", corruption)
+	}
+	if _, exists := allocator.AllocatedBlocks["buf"]; exists || len(allocator.Quarantine) != 1 || allocator.Quarantine[0].Free {
+		t.Errorf("El bloque corrupto debería quedar en cuarentena sin liberarse")
+	}
+	if stats := allocator.Stats(); stats.Quarantined != 1 || stats.QuarantineMemory != 16 || stats.FreeMemory != 48 {
+		t.Errorf("Las estadísticas no cuentan la cuarentena: %+v", stats)
+	}
+	var doubleFree *CorruptionError
+	if err := allocator.Free("buf"); !errors.As(err, &doubleFree) || !strings.Contains(doubleFree.Detail, "doble liberación") {
+		t.Errorf("Liberar otra vez un bloque en cuarentena es una doble liberación: %v", err)
+	}
+
+	// Escribir antes del inicio daña el canario delantero
+	allocator2, _ := NewBuddyAllocator(64)
+	_ = allocator2.EnableCheckedMode()
+	_ = allocator2.Reserve(4, "buf")
+	_ = allocator2.Write("buf", -1, []byte{0})
+	err = allocator2.CheckIntegrity()
+	if err == nil || !strings.Contains(err.Error(), "canario delantero") || !strings.Contains(err.Error(), "VERIFICAR") {
+		t.Errorf("No detectó el canario delantero dañado: %v", err)
+	}
+}
+
+// Test para detectar escrituras en memoria ya liberada
+func TestCheckedUseAfterFree(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(32)
+	_ = allocator.EnableCheckedMode()
+	_ = allocator.Reserve(4, "viejo")
+	addr := allocator.AllocatedBlocks["viejo"].Address
+	_ = allocator.Free("viejo")
+
+	allocator.Arena[addr+5] = 'x' // alguien sigue usando el puntero viejo
+
+	if err := allocator.CheckIntegrity(); err == nil || !strings.Contains(err.Error(), "uso después de liberar") {
+		t.Errorf("CheckIntegrity no detectó el uso después de liberar: %v", err)
+	}
+
+	err := allocator.Reserve(20, "nuevo")
+	var corruption *CorruptionError
+	if !errors.As(err, &corruption) {
+		t.Fatalf("Esperaba un CorruptionError al reservar, obtuve %v", err)
+	}
+	if corruption.Tag != "nuevo" || corruption.Operation != "RESERVAR" || corruption.Address != addr+5 {
+		t.Errorf("Reporte de corrupción incorrecto: %+v", corruption)
+	}
+
+	// El bloque se vuelve a envenenar, así que el siguiente intento funciona
+	if err := allocator.Reserve(20, "nuevo"); err != nil {
+		t.Errorf("El segundo intento debería funcionar: %v", err)
+	}
+}
+
+// Test para que un bloque rechazado por veneno vuelva por el camino normal y respete la fusión diferida
+func TestCheckedUseAfterFreeDeferred(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(64)
+	_ = allocator.EnableCheckedMode()
+	allocator.SetDeferredCoalescing(true, 4)
+	_ = allocator.Reserve(4, "viejo")
+	addr := allocator.AllocatedBlocks["viejo"].Address
+	_ = allocator.Free("viejo")
+	allocator.Arena[addr+5] = 'x'
+
+	var corruption *CorruptionError
+	if err := allocator.Reserve(4, "nuevo"); !errors.As(err, &corruption) {
+		t.Fatalf("Esperaba un CorruptionError al reservar, obtuve %v", err)
+	}
+	if allocator.pendingCoalesce() == 0 {
+		t.Errorf("El bloque rechazado debería volver al caché diferido en vez de fusionarse")
+	}
+	if allocator.FreeMemory() != 64 {
+		t.Errorf("No debería perderse memoria: %d libres", allocator.FreeMemory())
+	}
+}
+
+// Test para la doble liberación y los handles obsoletos
+func TestCheckedDoubleFreeAndStaleHandle(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(64)
+	_ = allocator.EnableCheckedMode()
+	_ = allocator.Reserve(4, "p1")
+	oldHandle, _ := allocator.HandleOf("p1")
+
+	if err := allocator.FreeHandle(oldHandle); err != nil {
+		t.Fatalf("No se pudo liberar con el handle: %v", err)
+	}
+	err := allocator.Free("p1")
+	if err == nil || !strings.Contains(err.Error(), "doble liberación") {
+		t.Errorf("No detectó la doble liberación por tag: %v", err)
+	}
+	err = allocator.FreeHandle(oldHandle)
+	if err == nil || !strings.Contains(err.Error(), "doble liberación") {
+		t.Errorf("No detectó la doble liberación por handle: %v", err)
+	}
+
+	// Se reutiliza el tag, el handle viejo ya no es válido
+	_ = allocator.Reserve(4, "p1")
+	newHandle, _ := allocator.HandleOf("p1")
+	if newHandle.Generation == oldHandle.Generation {
+		t.Errorf("La generación debería cambiar entre reservas")
+	}
+	err = allocator.FreeHandle(oldHandle)
+	if err == nil || !strings.Contains(err.Error(), "handle obsoleto") {
+		t.Errorf("No detectó el handle obsoleto: %v", err)
+	}
+	if _, exists := allocator.AllocatedBlocks["p1"]; !exists {
+		t.Errorf("El handle obsoleto no debería liberar la reserva nueva")
+	}
+}
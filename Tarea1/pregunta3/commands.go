@@ -0,0 +1,176 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"comandos"
+)
+
+// runLine ejecuta una línea del simulador y explica los errores de escritura. Devuelve
+// verdadero si hay que salir.
+func runLine(table *comandos.Conjunto[bool], line string) bool {
+	exit, err := table.Ejecutar(line)
+	var usage *comandos.ErrorUso
+	var unknown *comandos.ErrorDesconocido
+	switch {
+	case err == nil:
+		return exit
+	case errors.As(err, &unknown):
+		fmt.Printf("Error: Acción no reconocida. Acciones válidas: %s.\n", strings.Join(unknown.Opciones, ", "))
+	case errors.As(err, &usage):
+		fmt.Printf("Error: Formato incorrecto. Uso: %s\n", usage.Uso)
+	default:
+		fmt.Printf("Error: %v.\n", err)
+	}
+	return false
+}
+
+// actionPrompt arma el prompt de acciones con el uso de cada comando de la tabla
+func actionPrompt(table *comandos.Conjunto[bool]) string {
+	var usages []string
+	for _, cmd := range table.Comandos() {
+		usages = append(usages, cmd.Uso())
+	}
+	return "\nIngrese una acción (" + strings.Join(usages, " | ") + "): "
+}
+
+// simulatorCommands arma la tabla de acciones del simulador sobre el allocator. La tabla da
+// los usos del prompt y de los errores, la ayuda y lo que se completa con Tab.
+func simulatorCommands(allocator *BuddyAllocator) *comandos.Conjunto[bool] {
+	allocatedNames := func(args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		var names []string
+		for name := range allocator.GetAllocatedBlocks() {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	table := comandos.NuevoConjunto(
+		comandos.Comando[bool]{
+			Nombre: "RESERVAR", Argumentos: "<cantidad> <nombre> [alineación]",
+			Descripcion: "Reserva memoria con un nombre, alineada a la potencia de 2 dada.",
+			Min:         2, Max: 3,
+			Ejecutar: func(args []string) bool {
+				size, err := strconv.Atoi(args[0])
+				if err != nil {
+					fmt.Println("Error: La cantidad debe ser un número entero.")
+					return false
+				}
+				alignment := 1
+				if len(args) == 3 {
+					alignment, err = strconv.Atoi(args[2])
+					if err != nil {
+						fmt.Println("Error: La alineación debe ser un número entero.")
+						return false
+					}
+				}
+				name := args[1]
+				if err := allocator.ReserveAligned(size, alignment, name); err != nil {
+					fmt.Printf("Error al reservar: %v\n", err)
+				} else {
+					fmt.Printf("Memoria de %d unidades reservada para '%s'.\n", size, name)
+				}
+				return false
+			},
+		},
+		comandos.Comando[bool]{
+			Nombre: "RESERVAR_EN", Argumentos: "<dirección> <cantidad> <nombre>",
+			Descripcion: "Reserva memoria con un nombre empezando en una dirección fija.",
+			Min:         3, Max: 3,
+			Ejecutar: func(args []string) bool {
+				address, errAddr := strconv.Atoi(args[0])
+				size, errSize := strconv.Atoi(args[1])
+				if errAddr != nil || errSize != nil {
+					fmt.Println("Error: La dirección y la cantidad deben ser números enteros.")
+					return false
+				}
+				name := args[2]
+				if err := allocator.ReserveAt(address, size, name); err != nil {
+					fmt.Printf("Error al reservar: %v\n", err)
+				} else {
+					fmt.Printf("Memoria de %d unidades reservada para '%s' en la dirección %d.\n", size, name, address)
+				}
+				return false
+			},
+		},
+		comandos.Comando[bool]{
+			Nombre: "LIBERAR", Argumentos: "<nombre>",
+			Descripcion: "Libera la memoria reservada con ese nombre.",
+			Min:         1, Max: 1, Completar: allocatedNames,
+			Ejecutar: func(args []string) bool {
+				name := args[0]
+				if err := allocator.Free(name); err != nil {
+					fmt.Printf("Error al liberar: %v\n", err)
+				} else {
+					fmt.Printf("Memoria para '%s' liberada.\n", name)
+				}
+				return false
+			},
+		},
+		comandos.Comando[bool]{
+			Nombre:      "MOSTRAR",
+			Descripcion: "Muestra el árbol de bloques.",
+			Ejecutar: func(args []string) bool {
+				allocator.Show()
+				return false
+			},
+		},
+		comandos.Comando[bool]{
+			Nombre:      "ESTADISTICAS",
+			Descripcion: "Muestra la memoria ocupada, pedida y libre.",
+			Ejecutar: func(args []string) bool {
+				allocator.ShowStats()
+				return false
+			},
+		},
+		comandos.Comando[bool]{
+			Nombre:      "FUSIONAR",
+			Descripcion: "Fusiona los bloques pendientes de la fusión diferida.",
+			Ejecutar: func(args []string) bool {
+				allocator.Coalesce()
+				fmt.Println("Bloques pendientes fusionados.")
+				return false
+			},
+		},
+	)
+	table.Agregar(
+		comandos.Comando[bool]{
+			Nombre: "AYUDA", Argumentos: "[acción]",
+			Descripcion: "Muestra el uso de todas las acciones o de la dada.",
+			Max:         1,
+			Completar: func(args []string) []string {
+				if len(args) > 0 {
+					return nil
+				}
+				var names []string
+				for _, cmd := range table.Comandos() {
+					names = append(names, cmd.Nombre)
+				}
+				return names
+			},
+			Ejecutar: func(args []string) bool {
+				if err := table.Ayuda(os.Stdout, args); err != nil {
+					fmt.Printf("Error: No hay ninguna acción %s.\n", strings.ToUpper(args[0]))
+				}
+				return false
+			},
+		},
+		comandos.Comando[bool]{
+			Nombre:      "SALIR",
+			Descripcion: "Termina el simulador.",
+			Ejecutar: func(args []string) bool {
+				fmt.Println("Saliendo del simulador.")
+				return true
+			},
+		},
+	)
+	return table
+}
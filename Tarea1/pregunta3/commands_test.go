@@ -0,0 +1,28 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// Prueba que LIBERAR completa con los nombres reservados y que SALIR termina
+func TestSimulatorCommands(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(16)
+	table := simulatorCommands(allocator)
+	if runLine(table, `RESERVAR 2 "proceso A"`) || runLine(table, "RESERVAR 2 procesoB") {
+		t.Fatalf("RESERVAR no debería terminar el simulador")
+	}
+	if inicio, candidatos := table.Completar("liberar pro"); inicio != 8 || !slices.Equal(candidatos, []string{`"proceso A"`, "procesoB"}) {
+		t.Errorf("Completar = %d, %v; se esperaban los nombres reservados", inicio, candidatos)
+	}
+	if runLine(table, "LIBERAR") || runLine(table, "BORRAR x") {
+		t.Errorf("Los errores de escritura no deberían terminar el simulador")
+	}
+	if _, exists := allocator.GetAllocatedBlocks()["proceso A"]; !exists {
+		t.Errorf("El nombre entre comillas no se reservó")
+	}
+	if !runLine(table, "salir") {
+		t.Errorf("SALIR debería terminar el simulador")
+	}
+}
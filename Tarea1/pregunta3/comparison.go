@@ -0,0 +1,106 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// AllocatorFactory crea un allocator nuevo de una estrategia con el tamaño dado
+type AllocatorFactory struct {
+	Name string
+	New  func(totalBlocks int) (Allocator, error)
+}
+
+// DefaultAllocators devuelve todas las estrategias que se comparan
+func DefaultAllocators() []AllocatorFactory {
+	return []AllocatorFactory{
+		{Name: "buddy", New: func(n int) (Allocator, error) { return NewBuddyAllocator(n) }},
+		{Name: "buddy-diferido", New: newDeferredBuddyAllocator},
+		{Name: "first-fit", New: func(n int) (Allocator, error) { return NewFreeListAllocator(n, FirstFit) }},
+		{Name: "best-fit", New: func(n int) (Allocator, error) { return NewFreeListAllocator(n, BestFit) }},
+		{Name: "segregated", New: func(n int) (Allocator, error) { return NewSegregatedFitAllocator(n) }},
+		{Name: "tlsf", New: func(n int) (Allocator, error) { return NewTLSFAllocator(n) }},
+	}
+}
+
+// newDeferredBuddyAllocator crea un buddy system con fusión diferida para la comparación
+func newDeferredBuddyAllocator(totalBlocks int) (Allocator, error) {
+	allocator, err := NewBuddyAllocator(totalBlocks)
+	if err != nil {
+		return nil, err
+	}
+	allocator.SetDeferredCoalescing(true, 8)
+	return allocator, nil
+}
+
+// ComparisonConfig indica con qué parámetros se corre la comparación
+type ComparisonConfig struct {
+	TotalMemory int   // Tamaño de la memoria de cada allocator
+	Operations  int   // Cantidad de operaciones por carga
+	MaxSize     int   // Tamaño máximo de una reserva
+	Seed        int64 // Semilla para que las cargas sean reproducibles
+}
+
+// ComparisonResult es una fila de la comparación
+type ComparisonResult struct {
+	Allocator string
+	Workload  string
+	WorkloadResult
+}
+
+// RunComparison corre las mismas cargas sobre todas las estrategias
+func RunComparison(cfg ComparisonConfig, factories []AllocatorFactory) ([]ComparisonResult, error) {
+	var results []ComparisonResult
+	for _, workload := range Workloads {
+		ops, err := GenerateWorkload(workload, cfg.Seed, cfg.Operations, cfg.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, factory := range factories {
+			allocator, err := factory.New(cfg.TotalMemory)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", factory.Name, err)
+			}
+			results = append(results, ComparisonResult{
+				Allocator:      factory.Name,
+				Workload:       workload,
+				WorkloadResult: RunWorkload(allocator, ops),
+			})
+		}
+	}
+	return results, nil
+}
+
+// PrintComparisonTable imprime los resultados como una tabla alineada
+func PrintComparisonTable(w io.Writer, results []ComparisonResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "carga\testrategia\tops/s\tfallidas\tpico usado\tpico pedido\tfrag. externa\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%.0f\t%d\t%d\t%d\t%.3f\t\n",
+			r.Workload, r.Allocator, r.Throughput(), r.FailedReserves, r.PeakUsed, r.PeakRequested, r.AvgFragmentation)
+	}
+	tw.Flush()
+}
+
+// WriteComparisonCSV escribe los resultados en formato CSV
+func WriteComparisonCSV(w io.Writer, results []ComparisonResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"carga", "estrategia", "ops_por_segundo", "reservas_fallidas", "pico_usado", "pico_pedido", "fragmentacion_externa"})
+	for _, r := range results {
+		cw.Write([]string{
+			r.Workload,
+			r.Allocator,
+			strconv.FormatFloat(r.Throughput(), 'f', 0, 64),
+			strconv.Itoa(r.FailedReserves),
+			strconv.Itoa(r.PeakUsed),
+			strconv.Itoa(r.PeakRequested),
+			strconv.FormatFloat(r.AvgFragmentation, 'f', 4, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
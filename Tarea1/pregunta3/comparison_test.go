@@ -0,0 +1,124 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Test para checar que las cargas son reproducibles y bien formadas
+func TestGenerateWorkload(t *testing.T) {
+	ops1, err := GenerateWorkload(WorkloadMixed, 7, 500, 64)
+	if err != nil {
+		t.Fatalf("No se pudo generar la carga: %v", err)
+	}
+	ops2, _ := GenerateWorkload(WorkloadMixed, 7, 500, 64)
+	if len(ops1) != 500 {
+		t.Errorf("Esperaba 500 operaciones, obtuve %d", len(ops1))
+	}
+
+	live := make(map[string]bool)
+	for i, op := range ops1 {
+		if op != ops2[i] {
+			t.Fatalf("La misma semilla debería dar la misma carga")
+		}
+		if op.Reserve {
+			if op.Size < 1 || op.Size > 64 {
+				t.Errorf("Tamaño fuera de rango: %d", op.Size)
+			}
+			live[op.Tag] = true
+		} else {
+			if !live[op.Tag] {
+				t.Errorf("Se libera '%s' que no está vivo", op.Tag)
+			}
+			delete(live, op.Tag)
+		}
+	}
+
+	if _, err := GenerateWorkload("otra", 1, 10, 8); err == nil {
+		t.Errorf("Debería fallar con una carga desconocida")
+	}
+}
+
+// Test para las métricas de RunWorkload con una carga escrita a mano
+func TestRunWorkload(t *testing.T) {
+	allocator, _ := NewBuddyAllocator(16)
+	ops := []Operation{
+		{Reserve: true, Size: 3, Tag: "a"},  // usa 4
+		{Reserve: true, Size: 5, Tag: "b"},  // usa 8
+		{Reserve: true, Size: 16, Tag: "c"}, // falla
+		{Tag: "c"},                          // se ignora
+		{Tag: "a"},
+	}
+	result := RunWorkload(allocator, ops)
+	if result.FailedReserves != 1 {
+		t.Errorf("Esperaba 1 reserva fallida, obtuve %d", result.FailedReserves)
+	}
+	if result.Operations != 4 {
+		t.Errorf("La liberación ignorada no debería contarse: %d operaciones", result.Operations)
+	}
+	if result.PeakUsed != 12 || result.PeakRequested != 8 {
+		t.Errorf("Picos incorrectos: usado %d, pedido %d", result.PeakUsed, result.PeakRequested)
+	}
+	// Solo la primera reserva deja fragmentación (1 - 8/12) y se promedia entre 4 operaciones
+	if math.Abs(result.AvgFragmentation-1.0/12) > 1e-9 {
+		t.Errorf("Fragmentación promedio incorrecta: %v", result.AvgFragmentation)
+	}
+}
+
+// Test para la comparación completa en tabla y CSV
+func TestComparisonOutput(t *testing.T) {
+	cfg := ComparisonConfig{TotalMemory: 256, Operations: 300, MaxSize: 32, Seed: 3}
+	results, err := RunComparison(cfg, DefaultAllocators())
+	if err != nil {
+		t.Fatalf("Error en la comparación: %v", err)
+	}
+	if len(results) != len(Workloads)*len(DefaultAllocators()) {
+		t.Errorf("Cantidad de filas incorrecta: %d", len(results))
+	}
+
+	var table bytes.Buffer
+	PrintComparisonTable(&table, results)
+	for _, name := range []string{"buddy", "first-fit", "best-fit", "segregated", "tlsf", "frag. externa"} {
+		if !strings.Contains(table.String(), name) {
+			t.Errorf("La tabla no menciona '%s'", name)
+		}
+	}
+
+	var csvOut bytes.Buffer
+	if err := runComparisonCommand(&csvOut, cfg, "csv"); err != nil {
+		t.Fatalf("Error al escribir CSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != len(results)+1 || !strings.HasPrefix(lines[0], "carga,estrategia,ops_por_segundo") {
+		t.Errorf("CSV incorrecto:\n%s", csvOut.String())
+	}
+
+	if err := runComparisonCommand(&csvOut, cfg, "xml"); err == nil {
+		t.Errorf("Debería fallar con un formato desconocido")
+	}
+}
+
+// Benchmark que corre las mismas cargas sobre todas las estrategias
+func BenchmarkAllocators(b *testing.B) {
+	for _, workload := range Workloads {
+		ops, _ := GenerateWorkload(workload, 1, 10000, 256)
+		for _, factory := range DefaultAllocators() {
+			b.Run(workload+"/"+factory.Name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					allocator, _ := factory.New(4096)
+					for _, op := range ops {
+						if op.Reserve {
+							_ = allocator.Reserve(op.Size, op.Tag)
+						} else {
+							_ = allocator.Free(op.Tag)
+						}
+					}
+				}
+				b.ReportMetric(float64(len(ops)), "ops/op")
+			})
+		}
+	}
+}
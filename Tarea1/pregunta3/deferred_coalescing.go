@@ -0,0 +1,99 @@
+// Gabriel Seijas 19-00036
+package main
+
+import "math"
+
+// SetDeferredCoalescing activa o desactiva la fusión diferida. Con la fusión diferida un
+// bloque liberado se queda en su nivel para reutilizarse sin dividir de nuevo, y solo se
+// fusiona cuando el caché del nivel pasa de cacheLimit, cuando falta memoria o al llamar a
+// Coalesce. Con cacheLimit 0 no se guarda nada y cada bloque se fusiona al liberarse, así
+// el caché nunca crece sin límite. Al desactivarla se fusiona todo lo pendiente.
+func (ba *BuddyAllocator) SetDeferredCoalescing(enabled bool, cacheLimit int) {
+	if !enabled {
+		ba.Coalesce()
+	}
+	ba.DeferredCoalescing = enabled
+	ba.DeferredCacheLimit = cacheLimit
+	if ba.deferredCache == nil {
+		ba.deferredCache = make([][]*Block, len(ba.FreeLists))
+	}
+}
+
+// Coalesce fusiona todos los bloques que esperan en el caché de fusión diferida
+func (ba *BuddyAllocator) Coalesce() {
+	for level := range ba.deferredCache {
+		pending := ba.deferredCache[level]
+		ba.deferredCache[level] = nil
+		for _, block := range pending {
+			ba.coalesceIfStillFree(block)
+		}
+	}
+}
+
+// deferCoalesce guarda el bloque liberado en el caché de su nivel. Si el caché se pasa del
+// límite, el bloque más viejo se fusiona; con límite 0 es el mismo bloque recién liberado.
+func (ba *BuddyAllocator) deferCoalesce(block *Block) {
+	level := int(math.Log2(float64(block.Size)))
+	ba.deferredCache[level] = append(ba.deferredCache[level], block)
+	if len(ba.deferredCache[level]) > ba.DeferredCacheLimit {
+		oldest := ba.deferredCache[level][0]
+		ba.deferredCache[level] = ba.deferredCache[level][1:]
+		ba.coalesceIfStillFree(oldest)
+	}
+}
+
+// coalesceIfStillFree fusiona el bloque solo si sigue libre y sigue colgando del árbol
+// (pudo haberse reservado otra vez o quedar absorbido por la fusión de otro bloque)
+func (ba *BuddyAllocator) coalesceIfStillFree(block *Block) {
+	if block.Free && isAttached(block) {
+		ba.coalesce(block)
+	}
+}
+
+// isAttached indica si el bloque sigue colgando de su padre en el árbol
+func isAttached(block *Block) bool {
+	return block.Parent == nil || block.Parent.LeftChild == block || block.Parent.RightChild == block
+}
+
+// cachedBlock devuelve el bloque liberado más reciente del nivel que siga libre y alineado
+func (ba *BuddyAllocator) cachedBlock(level, alignment int) *Block {
+	if level >= len(ba.deferredCache) {
+		return nil
+	}
+	cache := ba.deferredCache[level]
+	for i := len(cache) - 1; i >= 0; i-- {
+		if cache[i].Free && isAttached(cache[i]) && cache[i].Address%alignment == 0 {
+			return cache[i]
+		}
+	}
+	return nil
+}
+
+// dropFromDeferredCache quita un bloque del caché porque se va a reutilizar
+func (ba *BuddyAllocator) dropFromDeferredCache(block *Block) {
+	if ba.deferredCache == nil {
+		return
+	}
+	level := int(math.Log2(float64(block.Size)))
+	for i, cached := range ba.deferredCache[level] {
+		if cached == block {
+			ba.deferredCache[level] = append(ba.deferredCache[level][:i], ba.deferredCache[level][i+1:]...)
+			return
+		}
+	}
+}
+
+// pendingCoalesce cuenta los bloques del caché que se fusionarían con Coalesce: los que
+// siguen libres en el árbol y tienen a su buddy libre. Los que ya se reutilizaron o
+// quedaron absorbidos, o cuyo buddy está ocupado, no cuentan.
+func (ba *BuddyAllocator) pendingCoalesce() int {
+	pending := 0
+	for _, level := range ba.deferredCache {
+		for _, block := range level {
+			if buddy := ba.findBuddy(block); block.Free && isAttached(block) && buddy != nil && buddy.Free {
+				pending++
+			}
+		}
+	}
+	return pending
+}
@@ -0,0 +1,117 @@
+// Gabriel Seijas 19-00036
+package main
+
+import "errors"
+
+// FitStrategy indica cómo elige el FreeListAllocator el tramo libre
+type FitStrategy int
+
+const (
+	FirstFit FitStrategy = iota // El primer tramo que alcance
+	BestFit                     // El tramo más pequeño que alcance
+)
+
+// String devuelve el nombre de la estrategia
+func (s FitStrategy) String() string {
+	if s == BestFit {
+		return "best-fit"
+	}
+	return "first-fit"
+}
+
+// FreeListAllocator maneja la memoria con una sola lista de tramos ordenada por dirección
+type FreeListAllocator struct {
+	TotalMemorySize int         // Tamaño total de la memoria
+	Strategy        FitStrategy // Estrategia para elegir el tramo libre
+	head            *segment    // Primer tramo de la memoria
+	allocated       map[string]*segment
+	used            int
+}
+
+// NewFreeListAllocator inicializa la memoria como un único tramo libre
+func NewFreeListAllocator(totalBlocks int, strategy FitStrategy) (*FreeListAllocator, error) {
+	if totalBlocks <= 0 {
+		return nil, errors.New("el tamaño total de bloques debe ser positivo")
+	}
+	return &FreeListAllocator{
+		TotalMemorySize: totalBlocks,
+		Strategy:        strategy,
+		head:            &segment{address: 0, size: totalBlocks, free: true},
+		allocated:       make(map[string]*segment),
+	}, nil
+}
+
+// Reserve reserva exactamente el tamaño pedido en el tramo que indique la estrategia
+func (fl *FreeListAllocator) Reserve(requestedSize int, tag string) error {
+	if requestedSize <= 0 {
+		return errors.New("el tamaño solicitado debe ser positivo")
+	}
+	if _, exists := fl.allocated[tag]; exists {
+		return errors.New("ya existe un bloque con ese nombre")
+	}
+
+	var found *segment
+	for s := fl.head; s != nil; s = s.next {
+		if !s.free || s.size < requestedSize {
+			continue
+		}
+		if fl.Strategy == FirstFit {
+			found = s
+			break
+		}
+		if found == nil || s.size < found.size {
+			found = s
+		}
+	}
+	if found == nil {
+		return errors.New("no hay suficiente memoria disponible para la solicitud")
+	}
+
+	found.split(requestedSize)
+	found.free = false
+	found.tag = tag
+	fl.allocated[tag] = found
+	fl.used += found.size
+	return nil
+}
+
+// Free libera el tramo y lo une con sus vecinos libres
+func (fl *FreeListAllocator) Free(tag string) error {
+	s, exists := fl.allocated[tag]
+	if !exists {
+		return errors.New("no existe un bloque con ese nombre")
+	}
+	delete(fl.allocated, tag)
+	fl.used -= s.size
+	s.free = true
+	s.tag = ""
+
+	if s.next != nil && s.next.free {
+		s.absorbNext()
+	}
+	if s.prev != nil && s.prev.free {
+		s.prev.absorbNext()
+	}
+	return nil
+}
+
+// UsedMemory devuelve cuántas unidades están ocupadas
+func (fl *FreeListAllocator) UsedMemory() int {
+	return fl.used
+}
+
+// FreeMemory devuelve cuántas unidades quedan libres
+func (fl *FreeListAllocator) FreeMemory() int {
+	return fl.TotalMemorySize - fl.used
+}
+
+// LargestFreeBlock devuelve el tamaño del mayor tramo libre
+func (fl *FreeListAllocator) LargestFreeBlock() int {
+	largest := 0
+	for s := fl.head; s != nil; s = s.next {
+		if s.free && s.size > largest {
+			largest = s.size
+		}
+	}
+	return largest
+}
@@ -0,0 +1,83 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test para first-fit: toma el primer hueco aunque haya uno más justo
+func TestFreeListFirstFit(t *testing.T) {
+	allocator, _ := NewFreeListAllocator(32, FirstFit)
+	_ = allocator.Reserve(8, "a")
+	_ = allocator.Reserve(4, "b")
+	_ = allocator.Reserve(2, "c")
+	_ = allocator.Reserve(4, "d")
+	_ = allocator.Free("a") // hueco de 8 en 0
+	_ = allocator.Free("c") // hueco de 2 en 12
+
+	_ = allocator.Reserve(2, "e")
+	if allocator.allocated["e"].address != 0 {
+		t.Errorf("First-fit debería usar el primer hueco, obtuve dirección %d", allocator.allocated["e"].address)
+	}
+	if allocator.UsedMemory() != 10 || allocator.FreeMemory() != 22 {
+		t.Errorf("Memoria usada incorrecta: %d", allocator.UsedMemory())
+	}
+}
+
+// Test para best-fit: toma el hueco más pequeño que alcance
+func TestFreeListBestFit(t *testing.T) {
+	allocator, _ := NewFreeListAllocator(32, BestFit)
+	_ = allocator.Reserve(8, "a")
+	_ = allocator.Reserve(4, "b")
+	_ = allocator.Reserve(2, "c")
+	_ = allocator.Reserve(4, "d")
+	_ = allocator.Free("a")
+	_ = allocator.Free("c")
+
+	_ = allocator.Reserve(2, "e")
+	if allocator.allocated["e"].address != 12 {
+		t.Errorf("Best-fit debería usar el hueco de 2, obtuve dirección %d", allocator.allocated["e"].address)
+	}
+}
+
+// Test para checar que al liberar se unen los tramos vecinos
+func TestFreeListCoalesce(t *testing.T) {
+	allocator, _ := NewFreeListAllocator(16, FirstFit)
+	_ = allocator.Reserve(4, "a")
+	_ = allocator.Reserve(4, "b")
+	_ = allocator.Reserve(4, "c")
+	_ = allocator.Free("a")
+	_ = allocator.Free("c") // se une con las 4 unidades libres del final
+	if allocator.LargestFreeBlock() != 8 {
+		t.Errorf("Antes de liberar b el mayor hueco debería ser 8, obtuve %d", allocator.LargestFreeBlock())
+	}
+	_ = allocator.Free("b")
+	if allocator.LargestFreeBlock() != 16 || allocator.head.next != nil {
+		t.Errorf("No se unieron todos los tramos al liberar")
+	}
+}
+
+// Test para los errores del FreeListAllocator
+func TestFreeListErrors(t *testing.T) {
+	if _, err := NewFreeListAllocator(0, FirstFit); err == nil {
+		t.Errorf("No dio error con tamaño cero")
+	}
+	allocator, _ := NewFreeListAllocator(8, FirstFit)
+	if err := allocator.Reserve(0, "x"); err == nil {
+		t.Errorf("No dio error con tamaño cero")
+	}
+	_ = allocator.Reserve(4, "x")
+	if err := allocator.Reserve(1, "x"); err == nil || !strings.Contains(err.Error(), "ya existe un bloque") {
+		t.Errorf("No detectó nombre duplicado")
+	}
+	if err := allocator.Reserve(5, "y"); err == nil || !strings.Contains(err.Error(), "no hay suficiente memoria") {
+		t.Errorf("No detectó falta de memoria")
+	}
+	if err := allocator.Free("z"); err == nil || !strings.Contains(err.Error(), "no existe un bloque") {
+		t.Errorf("No detectó liberar un bloque inexistente")
+	}
+	if FirstFit.String() != "first-fit" || BestFit.String() != "best-fit" {
+		t.Errorf("Nombres de estrategia incorrectos")
+	}
+}
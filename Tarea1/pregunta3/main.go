@@ -2,86 +2,100 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"testing"
+
+	"comandos"
 )
 
 func main() {
-	reader := bufio.NewReader(os.Stdin)
+	compare := flag.Bool("comparar", false, "compara el buddy system con otras estrategias y termina")
+	format := flag.String("formato", "tabla", "formato de la comparación: tabla o csv")
+	memory := flag.Int("memoria", 4096, "tamaño de la memoria para la comparación")
+	operations := flag.Int("operaciones", 20000, "operaciones por carga en la comparación")
+	maxSize := flag.Int("max", 256, "tamaño máximo de una reserva en la comparación")
+	seed := flag.Int64("semilla", 1, "semilla de las cargas sintéticas")
+	deferred := flag.Int("diferido", -1, "activa la fusión diferida con ese límite de caché por nivel (0 = sin caché)")
+	flag.Parse()
+
+	if *compare {
+		cfg := ComparisonConfig{TotalMemory: *memory, Operations: *operations, MaxSize: *maxSize, Seed: *seed}
+		if err := runComparisonCommand(os.Stdout, cfg, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	fmt.Println("--- Simulador de Manejador de Memoria (Buddy System) ---")
-	fmt.Print("Ingrese la cantidad total de bloques de memoria (potencia de 2 recomendada): ")
+	var allocator *BuddyAllocator
+	table := comandos.NuevoConjunto[bool]()
+	complete := func(line string) (int, []string) {
+		if allocator == nil {
+			return 0, nil
+		}
+		return table.Completar(line)
+	}
+	reader := comandos.NuevoLector(os.Stdin, os.Stdout, complete)
+	defer reader.Cerrar()
+
+	input, _ := reader.Leer("Ingrese la cantidad total de bloques de memoria (potencia de 2 recomendada): ")
+	if allocator = startAllocator(input, *deferred); allocator == nil {
+		return
+	}
 
-	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(input)
-	totalBlocks, err := strconv.Atoi(input)
+	table = simulatorCommands(allocator)
+	prompt := actionPrompt(table)
+	for {
+		line, ok := reader.Leer(prompt)
+		if !ok || runLine(table, line) {
+			return
+		}
+	}
+}
+
+// startAllocator crea el allocator con la cantidad de bloques escrita por el usuario y
+// anuncia cómo quedó. Con deferred >= 0 activa la fusión diferida. Devuelve nil si no se pudo.
+func startAllocator(input string, deferred int) *BuddyAllocator {
+	totalBlocks, err := strconv.Atoi(strings.TrimSpace(input))
 	if err != nil {
 		fmt.Println("Error: Cantidad de bloques inválida. Debe ser un número entero.")
-		return
+		return nil
 	}
 
 	allocator, err := NewBuddyAllocator(totalBlocks)
 	if err != nil {
 		fmt.Printf("Error al inicializar el Buddy System: %v\n", err)
-		return
+		return nil
 	}
 
 	fmt.Printf("Sistema Buddy inicializado con %d unidades de memoria.\n", allocator.TotalMemorySize)
+	if deferred >= 0 {
+		allocator.SetDeferredCoalescing(true, deferred)
+		fmt.Println("Fusión diferida activada. Use FUSIONAR para fusionar los bloques pendientes.")
+	}
+	return allocator
+}
 
-	for {
-		fmt.Print("\nIngrese una acción (RESERVAR <cantidad> <nombre> | LIBERAR <nombre> | MOSTRAR | SALIR): ")
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		parts := strings.Fields(input)
-
-		if len(parts) == 0 {
-			continue
-		}
-
-		action := strings.ToUpper(parts[0])
-
-		switch action {
-		case "RESERVAR":
-			if len(parts) != 3 {
-				fmt.Println("Error: Formato incorrecto. Uso: RESERVAR <cantidad> <nombre>")
-				continue
-			}
-			size, err := strconv.Atoi(parts[1])
-			if err != nil {
-				fmt.Println("Error: La cantidad debe ser un número entero.")
-				continue
-			}
-			name := parts[2]
-			err = allocator.Reserve(size, name)
-			if err != nil {
-				fmt.Printf("Error al reservar: %v\n", err)
-			} else {
-				fmt.Printf("Memoria de %d unidades reservada para '%s'.\n", size, name)
-			}
-		case "LIBERAR":
-			if len(parts) != 2 {
-				fmt.Println("Error: Formato incorrecto. Uso: LIBERAR <nombre>")
-				continue
-			}
-			name := parts[1]
-			err := allocator.Free(name)
-			if err != nil {
-				fmt.Printf("Error al liberar: %v\n", err)
-			} else {
-				fmt.Printf("Memoria para '%s' liberada.\n", name)
-			}
-		case "MOSTRAR":
-			allocator.Show()
-		case "SALIR":
-			fmt.Println("Saliendo del simulador.")
-			return
-		default:
-			fmt.Println("Error: Acción no reconocida. Acciones válidas: RESERVAR, LIBERAR, MOSTRAR, SALIR.")
-		}
+// runComparisonCommand corre la comparación de estrategias y la imprime en el formato pedido
+func runComparisonCommand(w io.Writer, cfg ComparisonConfig, format string) error {
+	results, err := RunComparison(cfg, DefaultAllocators())
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "tabla":
+		PrintComparisonTable(w, results)
+		return nil
+	case "csv":
+		return WriteComparisonCSV(w, results)
+	default:
+		return fmt.Errorf("formato desconocido '%s', usa tabla o csv", format)
 	}
 }
 
@@ -0,0 +1,134 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// SegregatedFitAllocator mantiene una lista libre por clase de tamaño (potencias de 2)
+type SegregatedFitAllocator struct {
+	TotalMemorySize int          // Tamaño total de la memoria
+	classes         [][]*segment // classes[k] guarda tramos libres de tamaño en [2^k, 2^(k+1))
+	allocated       map[string]*segment
+	used            int
+}
+
+// sizeClass devuelve la clase de un tamaño: floor(log2(size))
+func sizeClass(size int) int {
+	return bits.Len(uint(size)) - 1
+}
+
+// NewSegregatedFitAllocator inicializa la memoria como un único tramo libre
+func NewSegregatedFitAllocator(totalBlocks int) (*SegregatedFitAllocator, error) {
+	if totalBlocks <= 0 {
+		return nil, errors.New("el tamaño total de bloques debe ser positivo")
+	}
+	sa := &SegregatedFitAllocator{
+		TotalMemorySize: totalBlocks,
+		classes:         make([][]*segment, sizeClass(totalBlocks)+1),
+		allocated:       make(map[string]*segment),
+	}
+	sa.insert(&segment{address: 0, size: totalBlocks, free: true})
+	return sa, nil
+}
+
+// insert agrega un tramo libre a la lista de su clase
+func (sa *SegregatedFitAllocator) insert(s *segment) {
+	class := sizeClass(s.size)
+	sa.classes[class] = append(sa.classes[class], s)
+}
+
+// remove quita un tramo libre de la lista de su clase
+func (sa *SegregatedFitAllocator) remove(s *segment) {
+	class := sizeClass(s.size)
+	for i, candidate := range sa.classes[class] {
+		if candidate == s {
+			sa.classes[class] = append(sa.classes[class][:i], sa.classes[class][i+1:]...)
+			return
+		}
+	}
+}
+
+// Reserve busca desde la clase del tamaño pedido hacia arriba y parte el tramo encontrado
+func (sa *SegregatedFitAllocator) Reserve(requestedSize int, tag string) error {
+	if requestedSize <= 0 {
+		return errors.New("el tamaño solicitado debe ser positivo")
+	}
+	if _, exists := sa.allocated[tag]; exists {
+		return errors.New("ya existe un bloque con ese nombre")
+	}
+
+	var found *segment
+	for class := sizeClass(requestedSize); class < len(sa.classes) && found == nil; class++ {
+		for _, s := range sa.classes[class] {
+			if s.size >= requestedSize {
+				found = s
+				break
+			}
+		}
+	}
+	if found == nil {
+		return errors.New("no hay suficiente memoria disponible para la solicitud")
+	}
+
+	sa.remove(found)
+	if rest := found.split(requestedSize); rest != nil {
+		sa.insert(rest)
+	}
+	found.free = false
+	found.tag = tag
+	sa.allocated[tag] = found
+	sa.used += found.size
+	return nil
+}
+
+// Free libera el tramo, lo une con sus vecinos libres y lo devuelve a su clase
+func (sa *SegregatedFitAllocator) Free(tag string) error {
+	s, exists := sa.allocated[tag]
+	if !exists {
+		return errors.New("no existe un bloque con ese nombre")
+	}
+	delete(sa.allocated, tag)
+	sa.used -= s.size
+	s.free = true
+	s.tag = ""
+
+	if s.next != nil && s.next.free {
+		sa.remove(s.next)
+		s.absorbNext()
+	}
+	if s.prev != nil && s.prev.free {
+		sa.remove(s.prev)
+		s = s.prev
+		s.absorbNext()
+	}
+	sa.insert(s)
+	return nil
+}
+
+// UsedMemory devuelve cuántas unidades están ocupadas
+func (sa *SegregatedFitAllocator) UsedMemory() int {
+	return sa.used
+}
+
+// FreeMemory devuelve cuántas unidades quedan libres
+func (sa *SegregatedFitAllocator) FreeMemory() int {
+	return sa.TotalMemorySize - sa.used
+}
+
+// LargestFreeBlock revisa la clase más alta que tenga tramos libres
+func (sa *SegregatedFitAllocator) LargestFreeBlock() int {
+	for class := len(sa.classes) - 1; class >= 0; class-- {
+		largest := 0
+		for _, s := range sa.classes[class] {
+			if s.size > largest {
+				largest = s.size
+			}
+		}
+		if largest > 0 {
+			return largest
+		}
+	}
+	return 0
+}
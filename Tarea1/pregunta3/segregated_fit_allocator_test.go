@@ -0,0 +1,68 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test para checar que los tramos quedan en la clase correcta
+func TestSegregatedFitClasses(t *testing.T) {
+	allocator, _ := NewSegregatedFitAllocator(32)
+	if len(allocator.classes[5]) != 1 {
+		t.Fatalf("La memoria inicial debería estar en la clase 5")
+	}
+
+	_ = allocator.Reserve(5, "a")
+	// Sobran 27 unidades, que van a la clase 4 (16..31)
+	if len(allocator.classes[4]) != 1 || allocator.classes[4][0].size != 27 {
+		t.Errorf("El resto no quedó en la clase correcta")
+	}
+	if allocator.UsedMemory() != 5 || allocator.FreeMemory() != 27 || allocator.LargestFreeBlock() != 27 {
+		t.Errorf("Métricas incorrectas después de reservar")
+	}
+}
+
+// Test para reservar, liberar y volver a tener toda la memoria junta
+func TestSegregatedFitCoalesce(t *testing.T) {
+	allocator, _ := NewSegregatedFitAllocator(32)
+	_ = allocator.Reserve(3, "a")
+	_ = allocator.Reserve(7, "b")
+	_ = allocator.Reserve(2, "c")
+	_ = allocator.Free("b")
+	_ = allocator.Free("a")
+	_ = allocator.Free("c")
+
+	if allocator.LargestFreeBlock() != 32 || len(allocator.classes[5]) != 1 {
+		t.Errorf("No se restauró el tramo completo después de liberar todo")
+	}
+	for class, list := range allocator.classes[:5] {
+		if len(list) != 0 {
+			t.Errorf("La clase %d debería estar vacía", class)
+		}
+	}
+}
+
+// Test para los errores del SegregatedFitAllocator
+func TestSegregatedFitErrors(t *testing.T) {
+	if _, err := NewSegregatedFitAllocator(-1); err == nil {
+		t.Errorf("No dio error con tamaño negativo")
+	}
+	allocator, _ := NewSegregatedFitAllocator(8)
+	if err := allocator.Reserve(-2, "x"); err == nil {
+		t.Errorf("No dio error con tamaño negativo")
+	}
+	_ = allocator.Reserve(8, "x")
+	if err := allocator.Reserve(1, "x"); err == nil || !strings.Contains(err.Error(), "ya existe un bloque") {
+		t.Errorf("No detectó nombre duplicado")
+	}
+	if err := allocator.Reserve(1, "y"); err == nil || !strings.Contains(err.Error(), "no hay suficiente memoria") {
+		t.Errorf("No detectó falta de memoria")
+	}
+	if allocator.LargestFreeBlock() != 0 {
+		t.Errorf("Sin memoria libre el mayor hueco debería ser 0")
+	}
+	if err := allocator.Free("z"); err == nil || !strings.Contains(err.Error(), "no existe un bloque") {
+		t.Errorf("No detectó liberar un bloque inexistente")
+	}
+}
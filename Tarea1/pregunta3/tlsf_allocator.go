@@ -0,0 +1,195 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Cada nivel del primer índice de TLSF se divide en 2^tlsfSLLog2 sublistas
+const tlsfSLLog2 = 2
+
+// TLSFAllocator implementa Two-Level Segregated Fit: dos niveles de listas libres
+// con mapas de bits, de modo que encontrar un tramo y liberarlo cuesta O(1)
+type TLSFAllocator struct {
+	TotalMemorySize int          // Tamaño total de la memoria
+	flBitmap        uint         // Bit i encendido si hay tramos libres en el nivel i
+	slBitmaps       []uint       // Por nivel, bit j encendido si la sublista j tiene tramos
+	lists           [][]*segment // Cabeza de cada lista libre [nivel][sublista]
+	allocated       map[string]*segment
+	used            int
+}
+
+// tlsfMapping devuelve el nivel y la sublista donde se guarda un tramo de ese tamaño
+func tlsfMapping(size int) (int, int) {
+	fl := bits.Len(uint(size)) - 1
+	sl := ((size - 1<<fl) << tlsfSLLog2) >> fl
+	return fl, sl
+}
+
+// tlsfMappingSearch redondea el tamaño hacia arriba para que cualquier tramo de la lista alcance
+func tlsfMappingSearch(size int) (int, int) {
+	fl := bits.Len(uint(size)) - 1
+	if fl >= tlsfSLLog2 {
+		size += 1<<(fl-tlsfSLLog2) - 1
+	}
+	return tlsfMapping(size)
+}
+
+// NewTLSFAllocator inicializa la memoria como un único tramo libre
+func NewTLSFAllocator(totalBlocks int) (*TLSFAllocator, error) {
+	if totalBlocks <= 0 {
+		return nil, errors.New("el tamaño total de bloques debe ser positivo")
+	}
+	levels := bits.Len(uint(totalBlocks))
+	ta := &TLSFAllocator{
+		TotalMemorySize: totalBlocks,
+		slBitmaps:       make([]uint, levels),
+		lists:           make([][]*segment, levels),
+		allocated:       make(map[string]*segment),
+	}
+	for i := range ta.lists {
+		ta.lists[i] = make([]*segment, 1<<tlsfSLLog2)
+	}
+	ta.insert(&segment{address: 0, size: totalBlocks, free: true})
+	return ta, nil
+}
+
+// insert pone el tramo al inicio de su lista y enciende los bits correspondientes
+func (ta *TLSFAllocator) insert(s *segment) {
+	fl, sl := tlsfMapping(s.size)
+	s.prevFree = nil
+	s.nextFree = ta.lists[fl][sl]
+	if s.nextFree != nil {
+		s.nextFree.prevFree = s
+	}
+	ta.lists[fl][sl] = s
+	ta.flBitmap |= 1 << fl
+	ta.slBitmaps[fl] |= 1 << sl
+}
+
+// remove saca el tramo de su lista y apaga los bits si la lista queda vacía
+func (ta *TLSFAllocator) remove(s *segment) {
+	fl, sl := tlsfMapping(s.size)
+	if s.prevFree != nil {
+		s.prevFree.nextFree = s.nextFree
+	} else {
+		ta.lists[fl][sl] = s.nextFree
+	}
+	if s.nextFree != nil {
+		s.nextFree.prevFree = s.prevFree
+	}
+	s.prevFree, s.nextFree = nil, nil
+
+	if ta.lists[fl][sl] == nil {
+		ta.slBitmaps[fl] &^= 1 << sl
+		if ta.slBitmaps[fl] == 0 {
+			ta.flBitmap &^= 1 << fl
+		}
+	}
+}
+
+// findSuitable usa los mapas de bits para hallar la primera lista no vacía a partir de (fl, sl)
+func (ta *TLSFAllocator) findSuitable(fl, sl int) *segment {
+	if fl >= len(ta.slBitmaps) {
+		return nil
+	}
+	slMap := ta.slBitmaps[fl] & (^uint(0) << sl)
+	if slMap == 0 {
+		flMap := ta.flBitmap & (^uint(0) << (fl + 1))
+		if flMap == 0 {
+			return nil
+		}
+		fl = bits.TrailingZeros(flMap)
+		slMap = ta.slBitmaps[fl]
+	}
+	return ta.lists[fl][bits.TrailingZeros(slMap)]
+}
+
+// Reserve toma el primer tramo de la lista adecuada y devuelve lo que sobre a su lista
+func (ta *TLSFAllocator) Reserve(requestedSize int, tag string) error {
+	if requestedSize <= 0 {
+		return errors.New("el tamaño solicitado debe ser positivo")
+	}
+	if _, exists := ta.allocated[tag]; exists {
+		return errors.New("ya existe un bloque con ese nombre")
+	}
+
+	found := ta.findSuitable(tlsfMappingSearch(requestedSize))
+	if found == nil {
+		// El redondeo puede saltarse un tramo que sí alcanza dentro de la lista exacta
+		fl, sl := tlsfMapping(requestedSize)
+		if fl < len(ta.lists) {
+			for s := ta.lists[fl][sl]; s != nil; s = s.nextFree {
+				if s.size >= requestedSize {
+					found = s
+					break
+				}
+			}
+		}
+	}
+	if found == nil {
+		return errors.New("no hay suficiente memoria disponible para la solicitud")
+	}
+
+	ta.remove(found)
+	if rest := found.split(requestedSize); rest != nil {
+		ta.insert(rest)
+	}
+	found.free = false
+	found.tag = tag
+	ta.allocated[tag] = found
+	ta.used += found.size
+	return nil
+}
+
+// Free libera el tramo y lo une con sus vecinos físicos libres
+func (ta *TLSFAllocator) Free(tag string) error {
+	s, exists := ta.allocated[tag]
+	if !exists {
+		return errors.New("no existe un bloque con ese nombre")
+	}
+	delete(ta.allocated, tag)
+	ta.used -= s.size
+	s.free = true
+	s.tag = ""
+
+	if s.next != nil && s.next.free {
+		ta.remove(s.next)
+		s.absorbNext()
+	}
+	if s.prev != nil && s.prev.free {
+		ta.remove(s.prev)
+		s = s.prev
+		s.absorbNext()
+	}
+	ta.insert(s)
+	return nil
+}
+
+// UsedMemory devuelve cuántas unidades están ocupadas
+func (ta *TLSFAllocator) UsedMemory() int {
+	return ta.used
+}
+
+// FreeMemory devuelve cuántas unidades quedan libres
+func (ta *TLSFAllocator) FreeMemory() int {
+	return ta.TotalMemorySize - ta.used
+}
+
+// LargestFreeBlock revisa las listas del nivel más alto que tenga tramos libres
+func (ta *TLSFAllocator) LargestFreeBlock() int {
+	if ta.flBitmap == 0 {
+		return 0
+	}
+	fl := bits.Len(ta.flBitmap) - 1
+	largest := 0
+	for _, head := range ta.lists[fl] {
+		for s := head; s != nil; s = s.nextFree {
+			if s.size > largest {
+				largest = s.size
+			}
+		}
+	}
+	return largest
+}
@@ -0,0 +1,73 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test para el mapeo de tamaños a (nivel, sublista)
+func TestTLSFMapping(t *testing.T) {
+	cases := []struct{ size, fl, sl int }{
+		{1, 0, 0}, {2, 1, 0}, {3, 1, 2}, {4, 2, 0}, {7, 2, 3}, {16, 4, 0}, {23, 4, 1}, {31, 4, 3},
+	}
+	for _, c := range cases {
+		fl, sl := tlsfMapping(c.size)
+		if fl != c.fl || sl != c.sl {
+			t.Errorf("tlsfMapping(%d) = (%d, %d), esperaba (%d, %d)", c.size, fl, sl, c.fl, c.sl)
+		}
+	}
+
+	// La búsqueda redondea hacia la siguiente sublista
+	if fl, sl := tlsfMappingSearch(17); fl != 4 || sl != 1 {
+		t.Errorf("tlsfMappingSearch(17) = (%d, %d), esperaba (4, 1)", fl, sl)
+	}
+}
+
+// Test para reservar y liberar manteniendo los mapas de bits
+func TestTLSFReserveFree(t *testing.T) {
+	allocator, _ := NewTLSFAllocator(64)
+	_ = allocator.Reserve(10, "a")
+	_ = allocator.Reserve(20, "b")
+	if allocator.UsedMemory() != 30 || allocator.LargestFreeBlock() != 34 {
+		t.Errorf("Métricas incorrectas: usado %d, mayor hueco %d", allocator.UsedMemory(), allocator.LargestFreeBlock())
+	}
+
+	_ = allocator.Free("a")
+	_ = allocator.Free("b")
+	if allocator.FreeMemory() != 64 || allocator.LargestFreeBlock() != 64 {
+		t.Errorf("No se unieron los tramos al liberar todo")
+	}
+	if allocator.flBitmap != 1<<6 || allocator.slBitmaps[6] != 1 {
+		t.Errorf("Mapas de bits incorrectos: %b %b", allocator.flBitmap, allocator.slBitmaps[6])
+	}
+}
+
+// Test para el caso en que el redondeo se salta un tramo que sí alcanza
+func TestTLSFExactListFallback(t *testing.T) {
+	allocator, _ := NewTLSFAllocator(100)
+	if err := allocator.Reserve(100, "todo"); err != nil {
+		t.Errorf("Debería poder reservar toda la memoria: %v", err)
+	}
+}
+
+// Test para los errores del TLSFAllocator
+func TestTLSFErrors(t *testing.T) {
+	if _, err := NewTLSFAllocator(0); err == nil {
+		t.Errorf("No dio error con tamaño cero")
+	}
+	allocator, _ := NewTLSFAllocator(16)
+	if err := allocator.Reserve(0, "x"); err == nil {
+		t.Errorf("No dio error con tamaño cero")
+	}
+	_ = allocator.Reserve(16, "x")
+	if err := allocator.Reserve(1, "x"); err == nil || !strings.Contains(err.Error(), "ya existe un bloque") {
+		t.Errorf("No detectó nombre duplicado")
+	}
+	if err := allocator.Reserve(1, "y"); err == nil || !strings.Contains(err.Error(), "no hay suficiente memoria") {
+		t.Errorf("No detectó falta de memoria")
+	}
+	if err := allocator.Free("z"); err == nil || !strings.Contains(err.Error(), "no existe un bloque") {
+		t.Errorf("No detectó liberar un bloque inexistente")
+	}
+}
@@ -0,0 +1,43 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"comandos"
+	"comandos/transcripcion"
+)
+
+// newSession arma una sesión como la de main: la primera línea es la cantidad de bloques y
+// las siguientes son acciones. La salida se captura de stdout porque el simulador imprime ahí.
+func newSession(deferred int) func(out io.Writer) transcripcion.Sesion {
+	return func(out io.Writer) transcripcion.Sesion {
+		var table *comandos.Conjunto[bool]
+		return func(line string) bool {
+			exit := false
+			fmt.Fprint(out, captureOutput(func() {
+				if table == nil {
+					allocator := startAllocator(line, deferred)
+					if allocator == nil {
+						exit = true
+						return
+					}
+					table = simulatorCommands(allocator)
+					return
+				}
+				exit = runLine(table, line)
+			}))
+			return exit
+		}
+	}
+}
+
+// Corre las sesiones de testdata; las de testdata/diferido usan fusión diferida con caché 2.
+// Después de cambiar un mensaje a propósito se regeneran con: go test -run TestTranscripts -update
+func TestTranscripts(t *testing.T) {
+	transcripcion.Probar(t, filepath.Join("testdata", "*.txt"), newSession(-1))
+	transcripcion.Probar(t, filepath.Join("testdata", "diferido", "*.txt"), newSession(2))
+}
@@ -0,0 +1,133 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Tipos de carga sintética disponibles
+const (
+	WorkloadUniform = "uniforme" // Tamaños uniformes entre 1 y el máximo
+	WorkloadSmall   = "pequenos" // Solo tamaños pequeños (1 a 16)
+	WorkloadMixed   = "mixto"    // Mayoría pequeños con algunos grandes
+)
+
+// Workloads lista las cargas en el orden en que se comparan
+var Workloads = []string{WorkloadUniform, WorkloadSmall, WorkloadMixed}
+
+// Operation es un paso de la carga: reservar (Size > 0) o liberar el tag
+type Operation struct {
+	Reserve bool
+	Size    int
+	Tag     string
+}
+
+// WorkloadResult guarda las métricas de correr una carga sobre un allocator
+type WorkloadResult struct {
+	Operations       int           // Operaciones ejecutadas; no cuenta las liberaciones que se saltan
+	FailedReserves   int           // Reservas que no se pudieron atender
+	Duration         time.Duration // Tiempo gastado dentro de Reserve y Free
+	PeakUsed         int           // Máximo de unidades ocupadas al mismo tiempo
+	PeakRequested    int           // Máximo de unidades pedidas vivas al mismo tiempo
+	AvgFragmentation float64       // Promedio de fragmentación externa después de cada operación
+}
+
+// Throughput devuelve operaciones por segundo
+func (r WorkloadResult) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Operations) / r.Duration.Seconds()
+}
+
+// ExternalFragmentation mide qué tan partida está la memoria libre: 1 - mayorLibre/totalLibre
+func ExternalFragmentation(a Allocator) float64 {
+	free := a.FreeMemory()
+	if free == 0 {
+		return 0
+	}
+	return 1 - float64(a.LargestFreeBlock())/float64(free)
+}
+
+// GenerateWorkload crea una carga reproducible de reservas y liberaciones
+func GenerateWorkload(kind string, seed int64, operations, maxSize int) ([]Operation, error) {
+	rng := rand.New(rand.NewSource(seed))
+
+	var nextSize func() int
+	switch kind {
+	case WorkloadUniform:
+		nextSize = func() int { return 1 + rng.Intn(maxSize) }
+	case WorkloadSmall:
+		nextSize = func() int { return 1 + rng.Intn(min(16, maxSize)) }
+	case WorkloadMixed:
+		nextSize = func() int {
+			if rng.Intn(5) == 0 {
+				return 1 + rng.Intn(maxSize)
+			}
+			return 1 + rng.Intn(min(16, maxSize))
+		}
+	default:
+		return nil, fmt.Errorf("carga desconocida '%s'", kind)
+	}
+
+	ops := make([]Operation, 0, operations)
+	var live []string
+	for i := 0; len(ops) < operations; i++ {
+		// Un poco más de reservas que liberaciones para que la memoria se vaya llenando
+		if len(live) == 0 || rng.Intn(100) < 55 {
+			tag := fmt.Sprintf("t%d", i)
+			ops = append(ops, Operation{Reserve: true, Size: nextSize(), Tag: tag})
+			live = append(live, tag)
+		} else {
+			j := rng.Intn(len(live))
+			ops = append(ops, Operation{Tag: live[j]})
+			live[j] = live[len(live)-1]
+			live = live[:len(live)-1]
+		}
+	}
+	return ops, nil
+}
+
+// RunWorkload ejecuta la carga y mide tiempo, memoria pico y fragmentación
+func RunWorkload(a Allocator, ops []Operation) WorkloadResult {
+	var result WorkloadResult
+	reserved := make(map[string]int)
+	requested := 0
+	fragmentationSum := 0.0
+
+	for _, op := range ops {
+		if op.Reserve {
+			start := time.Now()
+			err := a.Reserve(op.Size, op.Tag)
+			result.Duration += time.Since(start)
+			result.Operations++
+			if err != nil {
+				result.FailedReserves++
+			} else {
+				reserved[op.Tag] = op.Size
+				requested += op.Size
+			}
+		} else if size, ok := reserved[op.Tag]; ok {
+			// Solo se libera lo que sí se pudo reservar
+			start := time.Now()
+			_ = a.Free(op.Tag)
+			result.Duration += time.Since(start)
+			result.Operations++
+			delete(reserved, op.Tag)
+			requested -= size
+		} else {
+			continue // Una liberación saltada no cambia nada, así que no es una muestra
+		}
+
+		result.PeakUsed = max(result.PeakUsed, a.UsedMemory())
+		result.PeakRequested = max(result.PeakRequested, requested)
+		fragmentationSum += ExternalFragmentation(a)
+	}
+
+	if result.Operations > 0 {
+		result.AvgFragmentation = fragmentationSum / float64(result.Operations)
+	}
+	return result
+}
@@ -0,0 +1,287 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"comandos"
+	"pregunta5/toolchain"
+)
+
+// Ejecuta un comando ya separado en palabras con la tabla de la sesión y explica los errores
+// de escritura
+func procesarPalabras(salida io.Writer, tabla *comandos.Conjunto[resultado], palabras []string) resultado {
+	res, err := tabla.EjecutarPalabras(palabras)
+	var uso *comandos.ErrorUso
+	var desconocido *comandos.ErrorDesconocido
+	switch {
+	case err == nil:
+		return res
+	case errors.As(err, &desconocido) && desconocido.Grupo == "":
+		fmt.Fprintln(salida, "Comando desconocido. Usa AYUDA para ver los comandos.")
+	case errors.As(err, &uso):
+		fmt.Fprintf(salida, "Error: %v\n", uso)
+	default:
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+	}
+	return resultadoError
+}
+
+// porPosicion sugiere para cada argumento lo que dé la fuente en su posición; nil no sugiere nada
+func porPosicion(fuentes ...func() []string) func(args []string) []string {
+	return func(args []string) []string {
+		if len(args) < len(fuentes) && fuentes[len(args)] != nil {
+			return fuentes[len(args)]()
+		}
+		return nil
+	}
+}
+
+// fijas sugiere siempre las mismas palabras, como las secciones de LISTAR
+func fijas(palabras ...string) func() []string {
+	return func() []string { return palabras }
+}
+
+// comandosSimulador arma la tabla de comandos del simulador sobre el catálogo. La tabla da
+// los usos de los mensajes de error, la ayuda, la bienvenida y lo que se completa con Tab.
+func comandosSimulador(salida io.Writer, tc *toolchain.Toolchain) *comandos.Conjunto[resultado] {
+	lenguajes := func() []string {
+		nombres := tc.Lenguajes()
+		for _, a := range tc.Alias() {
+			nombres = append(nombres, a.Nombre)
+		}
+		return nombres
+	}
+	programas := func() []string {
+		var nombres []string
+		for _, prog := range tc.Programas() {
+			nombres = append(nombres, prog.Nombre)
+		}
+		return nombres
+	}
+
+	tabla := comandos.NuevoConjunto(
+		comandos.Comando[resultado]{
+			Nombre: "DEFINIR PROGRAMA", Argumentos: "<nombre> <lenguaje>",
+			Descripcion: "Define un programa; el lenguaje puede llevar versión, como Python@3.11.",
+			Min:         2, Max: 2, Completar: porPosicion(nil, lenguajes),
+			Ejecutar: func(args []string) resultado { return handleDefinirPrograma(salida, tc, args[0], args[1]) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "DEFINIR INTERPRETE", Argumentos: "<lenguaje_base> <lenguaje> [costo]",
+			Descripcion: "Define un intérprete de lenguaje_base escrito en lenguaje.",
+			Min:         2, Max: 3, Completar: porPosicion(lenguajes, lenguajes),
+			Ejecutar: func(args []string) resultado { return handleDefinirInterprete(salida, tc, args) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "DEFINIR TRADUCTOR", Argumentos: "<lenguaje_base> <lenguaje_origen> <lenguaje_destino> [costo]",
+			Descripcion: "Define un traductor escrito en lenguaje_base.",
+			Min:         3, Max: 4, Completar: porPosicion(lenguajes, lenguajes, lenguajes),
+			Ejecutar: func(args []string) resultado { return handleDefinirTraductor(salida, tc, args) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "DEFINIR PLATAFORMA", Argumentos: "<nombre>",
+			Descripcion: "Declara otra plataforma nativa además de LOCAL.",
+			Min:         1, Max: 1,
+			Ejecutar: func(args []string) resultado { return handleDefinirPlataforma(salida, tc, args[0]) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "ALIAS", Argumentos: "[<alias> <lenguaje>]",
+			Descripcion: "Define otro nombre para un lenguaje o, sin argumentos, muestra los alias.",
+			Max:         2, Valido: func(args []string) bool { return len(args) != 1 }, Completar: porPosicion(nil, lenguajes),
+			Ejecutar: func(args []string) resultado { return handleAlias(salida, tc, args) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "ELIMINAR PROGRAMA", Argumentos: "<nombre>",
+			Descripcion: "Elimina un programa.",
+			Min:         1, Max: 1, Completar: porPosicion(programas),
+			Ejecutar: func(args []string) resultado { return handleEliminarPrograma(salida, tc, args[0]) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "ELIMINAR INTERPRETE", Argumentos: "<lenguaje_base> <lenguaje>",
+			Descripcion: "Elimina un intérprete y muestra qué programas dejan de ser ejecutables.",
+			Min:         2, Max: 2, Completar: porPosicion(lenguajes, lenguajes),
+			Ejecutar: func(args []string) resultado { return handleEliminarInterprete(salida, tc, args[0], args[1]) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "ELIMINAR TRADUCTOR", Argumentos: "<lenguaje_base> <lenguaje_origen> <lenguaje_destino>",
+			Descripcion: "Elimina un traductor y muestra qué programas dejan de ser ejecutables.",
+			Min:         3, Max: 3, Completar: porPosicion(lenguajes, lenguajes, lenguajes),
+			Ejecutar: func(args []string) resultado {
+				return handleEliminarTraductor(salida, tc, args[0], args[1], args[2])
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "CODIGO", Argumentos: "<nombre> <código...>",
+			Descripcion: "Guarda el código fuente de un programa para EJECUTAR.",
+			Min:         2, Max: -1, Completar: porPosicion(programas),
+			Ejecutar: func(args []string) resultado { return handleCodigo(salida, tc, args[0], strings.Join(args[1:], " ")) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "IMPLEMENTAR INTERPRETE", Argumentos: "<lenguaje_base> <lenguaje> <de=a>...",
+			Descripcion: "Da reglas de reescritura a un intérprete.",
+			Min:         2, Max: -1, Completar: porPosicion(lenguajes, lenguajes),
+			Ejecutar: func(args []string) resultado { return handleImplementar(salida, tc, "INTERPRETE", args) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "IMPLEMENTAR TRADUCTOR", Argumentos: "<lenguaje_base> <lenguaje_origen> <lenguaje_destino> <de=a>...",
+			Descripcion: "Da reglas de reescritura a un traductor.",
+			Min:         3, Max: -1, Completar: porPosicion(lenguajes, lenguajes, lenguajes),
+			Ejecutar: func(args []string) resultado { return handleImplementar(salida, tc, "TRADUCTOR", args) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "EJECUTAR", Argumentos: "<nombre> [entrada...]",
+			Descripcion: "Corre el programa en LOCAL por la cadena de herramientas con la entrada dada.",
+			Min:         1, Max: -1, Completar: porPosicion(programas),
+			Ejecutar: func(args []string) resultado { return handleEjecutar(salida, tc, args[0], args[1:]) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "EJECUTABLE", Argumentos: "<nombre> [CORTA|BARATA] [EN <plataforma>]",
+			Descripcion: "Busca una ruta para ejecutar el programa en LOCAL o en la plataforma.",
+			Min:         1, Max: 4, Completar: porPosicion(programas, fijas("CORTA", "BARATA", "EN")),
+			Valido: conPlataforma(1, 2),
+			Ejecutar: func(args []string) resultado {
+				args, plataforma := separarPlataforma(args)
+				criterio := "CORTA"
+				if len(args) == 2 {
+					criterio = strings.ToUpper(args[1])
+				}
+				return handleEjecutable(salida, tc, args[0], criterio, plataforma)
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "DIAGNOSTICO", Argumentos: "<nombre> [EN <plataforma>]",
+			Descripcion: "Explica por qué un programa no se puede ejecutar en LOCAL o en la plataforma y qué falta.",
+			Min:         1, Max: 3, Completar: porPosicion(programas, fijas("EN"), tc.Plataformas),
+			Valido: conPlataforma(1, 1),
+			Ejecutar: func(args []string) resultado {
+				args, plataforma := separarPlataforma(args)
+				return handleDiagnostico(salida, tc, args[0], plataforma)
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "RUTAS", Argumentos: "<nombre> [LARGO|COSTO] [max_rutas] [max_profundidad] [EN <plataforma>]",
+			Descripcion: "Lista todas las rutas del programa hasta LOCAL o hasta la plataforma.",
+			Min:         1, Max: 6, Completar: porPosicion(programas, fijas("LARGO", "COSTO", "EN")),
+			Valido: conPlataforma(1, 4),
+			Ejecutar: func(args []string) resultado {
+				args, plataforma := separarPlataforma(args)
+				return handleRutas(salida, tc, args, plataforma)
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "LISTAR", Argumentos: "[PROGRAMAS|INTERPRETES|TRADUCTORES|LENGUAJES|PLATAFORMAS]",
+			Descripcion: "Muestra lo definido en el catálogo.",
+			Max:         1, Completar: porPosicion(fijas("PROGRAMAS", "INTERPRETES", "TRADUCTORES", "LENGUAJES", "PLATAFORMAS")),
+			Ejecutar: func(args []string) resultado { return handleListar(salida, tc, args) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "DESCRIBIR", Argumentos: "<lenguaje> [EN <plataforma>]",
+			Descripcion: "Muestra los programas y las herramientas de un lenguaje, vistos desde LOCAL o desde la plataforma.",
+			Min:         1, Max: 3, Completar: porPosicion(lenguajes, fijas("EN"), tc.Plataformas),
+			Valido: conPlataforma(1, 1),
+			Ejecutar: func(args []string) resultado {
+				args, plataforma := separarPlataforma(args)
+				return handleDescribir(salida, tc, args[0], plataforma)
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "EJECUTABLES", Argumentos: "[EN <plataforma>]",
+			Descripcion: "Lista los programas que se pueden ejecutar en LOCAL o en la plataforma.",
+			Max:         2, Completar: porPosicion(fijas("EN"), tc.Plataformas),
+			Valido: conPlataforma(0, 0),
+			Ejecutar: func(args []string) resultado {
+				_, plataforma := separarPlataforma(args)
+				return handleEjecutables(salida, tc, plataforma)
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "BOOTSTRAPPING", Argumentos: "[EN <plataforma>]",
+			Descripcion: "Muestra las herramientas autoalojadas y cómo arrancar los ciclos en LOCAL o en la plataforma.",
+			Max:         2, Completar: porPosicion(fijas("EN"), tc.Plataformas),
+			Valido: conPlataforma(0, 0),
+			Ejecutar: func(args []string) resultado {
+				_, plataforma := separarPlataforma(args)
+				return handleBootstrapping(salida, tc, plataforma)
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "EXPORTAR", Argumentos: "<DOT|MERMAID> <archivo|-> [programa [EN <plataforma>]]",
+			Descripcion: "Exporta el grafo del catálogo, resaltando la ruta del programa a LOCAL o a la plataforma.",
+			Min:         2, Max: 5, Completar: porPosicion(fijas("DOT", "MERMAID"), nil, programas, fijas("EN"), tc.Plataformas),
+			// El EN solo tiene sentido si hay un programa cuya ruta resaltar
+			Valido: func(args []string) bool {
+				sinPlataforma, _ := separarPlataforma(args)
+				return len(sinPlataforma) == 3 || len(sinPlataforma) == len(args)
+			},
+			Ejecutar: func(args []string) resultado {
+				args, plataforma := separarPlataforma(args)
+				return handleExportar(salida, tc, args, plataforma)
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre: "GUARDAR", Argumentos: "<archivo>",
+			Descripcion: "Guarda el catálogo en un archivo JSON.",
+			Min:         1, Max: 1,
+			Ejecutar: func(args []string) resultado { return handleGuardar(salida, tc, args[0]) },
+		},
+		comandos.Comando[resultado]{
+			Nombre: "CARGAR", Argumentos: "<archivo>",
+			Descripcion: "Reemplaza el catálogo por el de un archivo JSON.",
+			Min:         1, Max: 1,
+			Ejecutar: func(args []string) resultado { return handleCargar(salida, tc, args[0]) },
+		},
+	)
+	tabla.Agregar(
+		comandos.Comando[resultado]{
+			Nombre: "AYUDA", Argumentos: "[comando]",
+			Descripcion: "Muestra el uso de todos los comandos o de los que empiezan con el dado.",
+			Max:         -1,
+			Completar: func(args []string) []string {
+				var nombres []string
+				for _, cmd := range tabla.Comandos() {
+					if nombre := strings.Fields(cmd.Nombre); len(nombre) > len(args) {
+						nombres = append(nombres, nombre[len(args)])
+					}
+				}
+				return nombres
+			},
+			Ejecutar: func(args []string) resultado {
+				if err := tabla.Ayuda(salida, args); err != nil {
+					fmt.Fprintf(salida, "Error: No hay ningún comando %s.\n", strings.ToUpper(strings.Join(args, " ")))
+					return resultadoError
+				}
+				return resultadoOK
+			},
+		},
+		comandos.Comando[resultado]{
+			Nombre:      "SALIR",
+			Descripcion: "Termina la sesión.",
+			Ejecutar: func(args []string) resultado {
+				fmt.Fprintln(salida, "Saliendo del simulador.")
+				return resultadoSalir
+			},
+		},
+	)
+	return tabla
+}
+
+// separarPlataforma quita el EN <plataforma> del final de los argumentos; sin EN la plataforma
+// es LOCAL
+func separarPlataforma(args []string) ([]string, string) {
+	if n := len(args); n >= 2 && strings.ToUpper(args[n-2]) == "EN" {
+		return args[:n-2], args[n-1]
+	}
+	return args, toolchain.LENGUAJE_LOCAL
+}
+
+// conPlataforma revisa que haya entre minimo y maximo argumentos sin contar el EN <plataforma>
+func conPlataforma(minimo, maximo int) func(args []string) bool {
+	return func(args []string) bool {
+		args, _ = separarPlataforma(args)
+		return len(args) >= minimo && len(args) <= maximo
+	}
+}
@@ -1,3 +1,7 @@
 module pregunta5
 
 go 1.25.2
+
+require comandos v0.0.0
+
+replace comandos => ../comandos
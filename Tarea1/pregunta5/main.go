@@ -3,193 +3,842 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"flag"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+
+	"comandos"
+	"pregunta5/toolchain"
 )
 
-// Estructura para guardar la info de un programa y su lenguaje
-type Programa struct {
-	Nombre   string
-	Lenguaje string
+func main() {
+	cargar := flag.String("cargar", "", "archivo JSON con el catálogo a cargar al iniciar")
+	guardar := flag.String("guardar", "", "archivo JSON donde guardar el catálogo al terminar")
+	script := flag.String("script", "", "ejecuta los comandos de este archivo sin interacción (- para la entrada estándar)")
+	nombres := flag.String("nombres", "mayusculas", "cómo comparar nombres de lenguajes: mayusculas (ignorarlas) o exactos")
+	servir := flag.String("servir", "", "atiende la API HTTP/JSON en esta dirección, como :8080, en vez del REPL")
+	flag.Parse()
+
+	if *servir != "" && (*guardar != "" || *script != "") {
+		fmt.Fprintln(os.Stderr, "Error: -servir no se puede combinar con -guardar ni con -script")
+		os.Exit(2)
+	}
+
+	normalizacion, err := toolchain.LeerNormalizacion(*nombres)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	tc := toolchain.New()
+	if err := tc.UsarNormalizacion(normalizacion); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if *cargar != "" {
+		cargado, err := toolchain.CargarArchivo(*cargar)
+		if err == nil {
+			if !flagDado("nombres") {
+				normalizacion = cargado.Normalizacion() // Sin -nombres manda la del archivo
+			}
+			err = mismaNormalizacion(cargado, normalizacion)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: No se pudo cargar '%s': %v\n", *cargar, err)
+			os.Exit(2)
+		}
+		tc = cargado
+	}
+
+	if *servir != "" {
+		srv := nuevoServidor(normalizacion)
+		if *cargar != "" {
+			srv.agregarEspacio(ESPACIO_PRINCIPAL, tc)
+		}
+		fmt.Fprintf(os.Stderr, "Atendiendo la API en %s\n", *servir)
+		if err := http.ListenAndServe(*servir, srv.rutas()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	codigo := 0
+	switch *script {
+	case "":
+		ejecutarREPL(os.Stdin, os.Stdout, tc)
+	case "-":
+		codigo = ejecutarScript(os.Stdin, os.Stdout, tc)
+	default:
+		archivo, err := os.Open(*script)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: No se pudo abrir el script: %v\n", err)
+			os.Exit(2)
+		}
+		codigo = ejecutarScript(archivo, os.Stdout, tc)
+		archivo.Close()
+	}
+
+	if *guardar != "" {
+		if err := tc.GuardarArchivo(*guardar); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: No se pudo guardar '%s': %v\n", *guardar, err)
+			os.Exit(2)
+		}
+	}
+	if codigo != 0 {
+		os.Exit(codigo)
+	}
 }
 
-// Estructura para guardar la info de un intérprete (qué lenguaje interpreta y en cuál está hecho)
-type Interprete struct {
-	LenguajeBase string
-	Lenguaje     string
+// termino dice cómo terminó un comando
+type termino int
+
+const (
+	terminoOK           termino = iota
+	terminoError                // Comando mal escrito o sobre algo que no existe
+	terminoNoEjecutable         // La consulta se hizo bien pero no hay ruta hasta la plataforma
+	terminoSalir                // Se pidió terminar la sesión
+)
+
+// consulta identifica una pregunta de ruta: el programa y la plataforma, como los conoce el catálogo
+type consulta struct {
+	programa, plataforma string
 }
 
-// Estructura para guardar la info de un traductor (en qué lenguaje está hecho, de cuál a cuál traduce)
-type Traductor struct {
-	LenguajeBase    string
-	LenguajeOrigen  string
-	LenguajeDestino string
+// resultado indica cómo terminó un comando; el modo script lo usa para el código de salida.
+// EJECUTABLE y RUTAS dicen además qué consulta respondieron.
+type resultado struct {
+	termino  termino
+	consulta consulta
 }
 
-// Diccionarios para guardar los programas, intérpretes y traductores definidos
-var programas = make(map[string]Programa)
-var interpretes = make(map[string]Interprete)
-var traductores = make(map[string]Traductor)
+var (
+	resultadoOK           = resultado{termino: terminoOK}
+	resultadoError        = resultado{termino: terminoError}
+	resultadoNoEjecutable = resultado{termino: terminoNoEjecutable}
+	resultadoSalir        = resultado{termino: terminoSalir}
+)
 
-// El lenguaje LOCAL representa el que la compu puede ejecutar directamente
-const LENGUAJE_LOCAL = "LOCAL"
+// de marca el resultado como la respuesta a la consulta del programa en la plataforma
+func (r resultado) de(programa, plataforma string) resultado {
+	r.consulta = consulta{programa, plataforma}
+	return r
+}
 
-func main() {
-	fmt.Println("Simulador de Programas, Intérpretes y Traductores")
-	fmt.Println("Comandos:")
-	fmt.Println("  DEFINIR PROGRAMA <nombre> <lenguaje>")
-	fmt.Println("  DEFINIR INTERPRETE <lenguaje_base> <lenguaje>")
-	fmt.Println("  DEFINIR TRADUCTOR <lenguaje_base> <lenguaje_origen> <lenguaje_destino>")
-	fmt.Println("  EJECUTABLE <nombre>")
-	fmt.Println("  SALIR")
-
-	scanner := bufio.NewScanner(os.Stdin)
+// Lee comandos de la entrada y los aplica sobre el catálogo hasta SALIR o fin de la entrada.
+// En una terminal Tab completa los comandos, los programas y los lenguajes conocidos.
+func ejecutarREPL(entrada io.Reader, salida io.Writer, tc *toolchain.Toolchain) {
+	fmt.Fprintln(salida, "Simulador de Programas, Intérpretes y Traductores")
+	fmt.Fprintln(salida, "Comandos:")
+	tabla := comandosSimulador(salida, tc)
+	for _, cmd := range tabla.Comandos() {
+		fmt.Fprintf(salida, "  %s\n", cmd.Uso())
+	}
+
+	lector := comandos.NuevoLector(entrada, salida, tabla.Completar)
+	defer lector.Cerrar()
 	for {
-		fmt.Print("> ")
-		scanner.Scan()
-		input := scanner.Text()
-		parts := strings.Fields(input) // Separa el input por espacios
+		linea, ok := lector.Leer("> ")
+		if !ok {
+			return
+		}
+		if procesarLinea(salida, tabla, linea) == resultadoSalir {
+			return
+		}
+	}
+}
 
-		if len(parts) == 0 {
+// Ejecuta un script de comandos sin bienvenida ni prompts y devuelve el código de salida:
+// 0 si la última consulta EJECUTABLE o RUTAS de cada programa en cada plataforma encontró
+// ruta, 1 si alguna no la encontró y 2 si algún comando tuvo un error. Las líneas que
+// empiezan con # se ignoran.
+func ejecutarScript(entrada io.Reader, salida io.Writer, tc *toolchain.Toolchain) int {
+	tabla := comandosSimulador(salida, tc)
+	consultas := make(map[consulta]bool)
+	huboError := false
+
+	scanner := bufio.NewScanner(entrada)
+	for scanner.Scan() {
+		linea := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(linea, "#") {
 			continue
 		}
+		res := procesarLinea(salida, tabla, linea)
+		if res == resultadoSalir {
+			break
+		}
+		if res == resultadoError {
+			huboError = true
+			continue
+		}
+		if res.consulta != (consulta{}) {
+			consultas[res.consulta] = res.termino == terminoOK
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(salida, "Error: No se pudo leer el script: %v\n", err)
+		huboError = true
+	}
 
-		command := strings.ToUpper(parts[0])
+	if huboError {
+		return 2
+	}
+	for _, ok := range consultas {
+		if !ok {
+			return 1
+		}
+	}
+	return 0
+}
 
-		switch command {
-		case "DEFINIR":
-			handleDefinir(parts[1:])
-		case "EJECUTABLE":
-			if len(parts) == 2 {
-				handleEjecutable(parts[1])
-			} else {
-				fmt.Println("Error: Uso EJECUTABLE <nombre>")
-			}
-		case "SALIR":
-			fmt.Println("Saliendo del simulador.")
-			return
-		default:
-			fmt.Println("Comando no permitido. Tienes que usar DEFINIR, EJECUTABLE o SALIR.")
+// Separa una línea en palabras, respetando las comillas, y ejecuta el comando que corresponda
+func procesarLinea(salida io.Writer, tabla *comandos.Conjunto[resultado], linea string) resultado {
+	palabras, err := comandos.Separar(linea)
+	if err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+	return procesarPalabras(salida, tabla, palabras)
+}
+
+// Define un programa; el lenguaje puede llevar una versión concreta
+func handleDefinirPrograma(salida io.Writer, tc *toolchain.Toolchain, nombre, lenguaje string) resultado {
+	if err := tc.DefinirPrograma(nombre, lenguaje); err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+	fmt.Fprintf(salida, "Programa '%s' en %s definido.\n", nombre, lenguaje)
+	return resultadoOK
+}
+
+// Define un intérprete con el costo opcional que viene después de los lenguajes
+func handleDefinirInterprete(salida io.Writer, tc *toolchain.Toolchain, args []string) resultado {
+	lenguajeBase := args[0]
+	lenguaje := args[1]
+	costo, ok := leerCosto(salida, args[2:])
+	if !ok {
+		return resultadoError
+	}
+	if err := tc.DefinirInterpreteConCosto(lenguajeBase, lenguaje, costo); err != nil {
+		return avisoOError(salida, err)
+	}
+	fmt.Fprintf(salida, "Intérprete de %s en %s definido.\n", lenguajeBase, lenguaje)
+	return resultadoOK
+}
+
+// Define un traductor con el costo opcional que viene después de los lenguajes
+func handleDefinirTraductor(salida io.Writer, tc *toolchain.Toolchain, args []string) resultado {
+	lenguajeBase := args[0]
+	lenguajeOrigen := args[1]
+	lenguajeDestino := args[2]
+	costo, ok := leerCosto(salida, args[3:])
+	if !ok {
+		return resultadoError
+	}
+	if err := tc.DefinirTraductorConCosto(lenguajeBase, lenguajeOrigen, lenguajeDestino, costo); err != nil {
+		return avisoOError(salida, err)
+	}
+	fmt.Fprintf(salida, "Traductor de %s de %s a %s definido.\n", lenguajeBase, lenguajeOrigen, lenguajeDestino)
+	return resultadoOK
+}
+
+// Declara otra plataforma nativa además de LOCAL
+func handleDefinirPlataforma(salida io.Writer, tc *toolchain.Toolchain, nombre string) resultado {
+	if err := tc.DefinirPlataforma(nombre); err != nil {
+		return avisoOError(salida, err)
+	}
+	fmt.Fprintf(salida, "Plataforma %s definida.\n", nombre)
+	return resultadoOK
+}
+
+// Una definición repetida solo se avisa y no cuenta como error; cualquier otro problema, como una
+// versión mal escrita, sí
+func avisoOError(salida io.Writer, err error) resultado {
+	if toolchain.EsRepetido(err) {
+		fmt.Fprintf(salida, "Aviso: %v.\n", err)
+		return resultadoOK
+	}
+	fmt.Fprintf(salida, "Error: %v.\n", err)
+	return resultadoError
+}
+
+// Define un alias para un lenguaje o, sin argumentos, muestra los que hay
+func handleAlias(salida io.Writer, tc *toolchain.Toolchain, args []string) resultado {
+	if len(args) == 0 {
+		alias := tc.Alias()
+		if len(alias) == 0 {
+			fmt.Fprintln(salida, "No hay alias definidos.")
+			return resultadoOK
+		}
+		fmt.Fprintln(salida, "Alias:")
+		for _, a := range alias {
+			fmt.Fprintf(salida, "  %s = %s\n", a.Nombre, a.Lenguaje)
 		}
+		return resultadoOK
 	}
+	if err := tc.DefinirAlias(args[0], args[1]); err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+	fmt.Fprintf(salida, "Alias %s definido para %s.\n", args[0], tc.NombreLenguaje(args[1]))
+	return resultadoOK
 }
 
-// Procesa el comando DEFINIR y guarda la info según el tipo
-func handleDefinir(args []string) {
-	if len(args) < 1 {
-		fmt.Println("Error: Uso DEFINIR <tipo> [argumentos]")
-		return
+// Elimina un programa del catálogo
+func handleEliminarPrograma(salida io.Writer, tc *toolchain.Toolchain, nombre string) resultado {
+	if err := tc.EliminarPrograma(nombre); err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+	fmt.Fprintf(salida, "Programa '%s' eliminado.\n", nombre)
+	return resultadoOK
+}
+
+// Elimina un intérprete y muestra qué programas dejaron de ser ejecutables
+func handleEliminarInterprete(salida io.Writer, tc *toolchain.Toolchain, lenguajeBase, lenguaje string) resultado {
+	afectados, err := tc.EliminarInterprete(lenguajeBase, lenguaje)
+	if err == nil {
+		fmt.Fprintf(salida, "Intérprete de %s en %s eliminado.\n", lenguajeBase, lenguaje)
 	}
+	return mostrarAfectados(salida, afectados, err)
+}
 
-	tipo := strings.ToUpper(args[0])
-	switch tipo {
-	case "PROGRAMA":
-		if len(args) == 3 {
-			nombre := args[1]
-			lenguaje := args[2]
-			programas[nombre] = Programa{Nombre: nombre, Lenguaje: lenguaje}
-			fmt.Printf("Programa '%s' en %s definido.\n", nombre, lenguaje)
-		} else {
-			fmt.Println("Error: Se usa de la siguiente forma 'DEFINIR PROGRAMA <nombre> <lenguaje>'")
-		}
-	case "INTERPRETE":
-		if len(args) == 3 {
-			lenguajeBase := args[1]
-			lenguaje := args[2]
-			interpretes[lenguajeBase] = Interprete{LenguajeBase: lenguajeBase, Lenguaje: lenguaje}
-			fmt.Printf("Intérprete de %s en %s definido.\n", lenguajeBase, lenguaje)
-		} else {
-			fmt.Println("Error: Se usa de la siguiente forma 'DEFINIR INTERPRETE <lenguaje_base> <lenguaje>'")
-		}
-	case "TRADUCTOR":
-		if len(args) == 4 {
-			lenguajeBase := args[1]
-			lenguajeOrigen := args[2]
-			lenguajeDestino := args[3]
-			// La clave del traductor es una combinación de sus lenguajes
-			traductores[fmt.Sprintf("%s-%s-%s", lenguajeBase, lenguajeOrigen, lenguajeDestino)] = Traductor{
-				LenguajeBase:    lenguajeBase,
-				LenguajeOrigen:  lenguajeOrigen,
-				LenguajeDestino: lenguajeDestino,
-			}
-			fmt.Printf("Traductor de %s de %s a %s definido.\n", lenguajeBase, lenguajeOrigen, lenguajeDestino)
-		} else {
-			fmt.Println("Error: Se usa de la siguiente forma ' DEFINIR TRADUCTOR <lenguaje_base> <lenguaje_origen> <lenguaje_destino>'")
+// Elimina un traductor y muestra qué programas dejaron de ser ejecutables
+func handleEliminarTraductor(salida io.Writer, tc *toolchain.Toolchain, lenguajeBase, lenguajeOrigen, lenguajeDestino string) resultado {
+	afectados, err := tc.EliminarTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino)
+	if err == nil {
+		fmt.Fprintf(salida, "Traductor de %s de %s a %s eliminado.\n", lenguajeBase, lenguajeOrigen, lenguajeDestino)
+	}
+	return mostrarAfectados(salida, afectados, err)
+}
+
+// Muestra el error de una eliminación o los programas que ya no se pueden ejecutar
+func mostrarAfectados(salida io.Writer, afectados []toolchain.Afectado, err error) resultado {
+	if err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+
+	if len(afectados) == 0 {
+		fmt.Fprintln(salida, "Ningún programa dejó de ser ejecutable.")
+		return resultadoOK
+	}
+	fmt.Fprintln(salida, "Programas que ya no se pueden ejecutar:")
+	for _, a := range afectados {
+		fmt.Fprintf(salida, "  %s (lenguaje: %s) en %s\n", a.Nombre, a.Lenguaje, strings.Join(a.Plataformas, ", "))
+	}
+	return resultadoOK
+}
+
+// Guarda el código fuente de un programa para poder correrlo con EJECUTAR
+func handleCodigo(salida io.Writer, tc *toolchain.Toolchain, nombre, codigo string) resultado {
+	if err := tc.DefinirCodigo(nombre, codigo); err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+	fmt.Fprintf(salida, "Código del programa '%s' guardado.\n", nombre)
+	return resultadoOK
+}
+
+// Le da a una herramienta reglas de reescritura palabra por palabra. Cada regla es de=a, y
+// si el reemplazo tiene varias palabras se separan con comas (por ejemplo cuadrado=dup,*).
+// El tipo es INTERPRETE o TRADUCTOR y args empieza con los lenguajes de la herramienta.
+func handleImplementar(salida io.Writer, tc *toolchain.Toolchain, tipo string, args []string) resultado {
+	lenguajes := 2
+	if tipo == "TRADUCTOR" {
+		lenguajes = 3
+	}
+
+	var reglas []toolchain.Regla
+	for _, texto := range args[lenguajes:] {
+		de, a, ok := strings.Cut(texto, "=")
+		if !ok || de == "" {
+			fmt.Fprintf(salida, "Error: La regla '%s' debe tener la forma de=a.\n", texto)
+			return resultadoError
 		}
-	default:
-		fmt.Println("Error: Tipo de definición desconocido. Usa PROGRAMA, INTERPRETE o TRADUCTOR.")
+		reglas = append(reglas, toolchain.Regla{De: de, A: strings.ReplaceAll(a, ",", " ")})
+	}
+
+	var err error
+	impl := toolchain.Reescritura(reglas...)
+	if tipo == "INTERPRETE" {
+		err = tc.ImplementarInterprete(args[0], args[1], impl)
+	} else {
+		err = tc.ImplementarTraductor(args[0], args[1], args[2], impl)
+	}
+	if err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+	fmt.Fprintf(salida, "Implementación con %d regla(s) registrada.\n", len(reglas))
+	return resultadoOK
+}
+
+// Corre el programa por la cadena de herramientas y muestra cada etapa y la salida de LOCAL.
+// Solo LOCAL tiene una máquina que corra código, así que no hay opción EN.
+func handleEjecutar(salida io.Writer, tc *toolchain.Toolchain, nombre string, args []string) resultado {
+	var entrada []int64
+	for _, arg := range args {
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			fmt.Fprintln(salida, "Error: La entrada debe ser una lista de números enteros.")
+			return resultadoError
+		}
+		entrada = append(entrada, n)
+	}
+
+	ejecucion, err := tc.Ejecutar(nombre, entrada)
+	for _, etapa := range ejecucion.Etapas {
+		fmt.Fprintf(salida, "  %s: %s\n", etapa.Lenguaje, etapa.Codigo)
 	}
+	if err != nil {
+		fmt.Fprintf(salida, "Error: %v.\n", err)
+		return resultadoError
+	}
+	var numeros []string
+	for _, n := range ejecucion.Salida {
+		numeros = append(numeros, strconv.FormatInt(n, 10))
+	}
+	fmt.Fprintf(salida, "Salida: %s\n", strings.Join(numeros, " "))
+	return resultadoOK
 }
 
-// Busca si el programa puede ejecutarse en LOCAL y muestra la ruta si existe
-func handleEjecutable(nombrePrograma string) {
-	prog, ok := programas[nombrePrograma]
+// Lee el costo opcional de una herramienta; si no viene se usa el costo por defecto
+func leerCosto(salida io.Writer, args []string) (float64, bool) {
+	if len(args) == 0 {
+		return toolchain.COSTO_POR_DEFECTO, true
+	}
+	costo, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || costo < 0 {
+		fmt.Fprintln(salida, "Error: El costo debe ser un número no negativo.")
+		return 0, false
+	}
+	return costo, true
+}
+
+// Revisa que la plataforma esté definida y devuelve su nombre en el catálogo
+func leerPlataforma(salida io.Writer, tc *toolchain.Toolchain, plataforma string) (string, bool) {
+	if !slices.Contains(tc.Plataformas(), tc.NombreLenguaje(plataforma)) {
+		fmt.Fprintf(salida, "Error: Plataforma '%s' no definida.\n", plataforma)
+		return "", false
+	}
+	return tc.NombreLenguaje(plataforma), true
+}
+
+// Busca si el programa puede ejecutarse en la plataforma y muestra la ruta si existe.
+// El criterio CORTA usa la ruta con menos pasos y BARATA la de menor costo total. Si algún
+// traductor de la ruta corre en otra plataforma se avisa de la compilación cruzada.
+func handleEjecutable(salida io.Writer, tc *toolchain.Toolchain, nombrePrograma, criterio, plataforma string) resultado {
+	if criterio != "CORTA" && criterio != "BARATA" {
+		fmt.Fprintln(salida, "Error: El criterio de ruta debe ser CORTA o BARATA.")
+		return resultadoError
+	}
+	plataforma, ok := leerPlataforma(salida, tc, plataforma)
 	if !ok {
-		fmt.Printf("Error: El Programa '%s' no definido.\n", nombrePrograma)
-		return
+		return resultadoError
+	}
+	prog, ok := tc.Programa(nombrePrograma)
+	if !ok {
+		fmt.Fprintf(salida, "Error: Programa '%s' no definido.\n", nombrePrograma)
+		return resultadoError
 	}
 
-	fmt.Printf("Intentando hacer ejecutable el programa '%s' (lenguaje: %s).\n", prog.Nombre, prog.Lenguaje)
+	fmt.Fprintf(salida, "Intentando hacer ejecutable el programa '%s' (lenguaje: %s).\n", prog.Nombre, prog.Lenguaje)
 
-	// Busca la ruta para ejecutar el programa en LOCAL
-	path, found := encontrarRutaEjecucion(prog.Lenguaje, LENGUAJE_LOCAL)
+	// Busca la ruta para ejecutar el programa en la plataforma
+	var ruta toolchain.Ruta
+	var found bool
+	if criterio == "BARATA" {
+		ruta, found = tc.RutaMasBarataEn(prog.Lenguaje, plataforma)
+	} else {
+		ruta, found = tc.RutaMasCortaEn(prog.Lenguaje, plataforma)
+	}
 
-	if found {
-		fmt.Printf("El programa '%s' puede ser ejecutado en LOCAL siguiendo la ruta: %s\n", prog.Nombre, strings.Join(path, " -> "))
+	if !found {
+		fmt.Fprintf(salida, "No se encontró una ruta para ejecutar el programa '%s' en %s.\n", prog.Nombre, plataforma)
+		// Un programa sin versión no entra en los rangos, aunque alguna versión sí se ejecute
+		if versiones := tc.VersionesEjecutables(prog.Lenguaje, plataforma); len(versiones) > 0 {
+			fmt.Fprintf(salida, "Sí se pueden ejecutar las versiones %s; define el programa con una de ellas.\n", strings.Join(versiones, ", "))
+		}
+		if plataforma == toolchain.LENGUAJE_LOCAL {
+			fmt.Fprintf(salida, "Usa DIAGNOSTICO %s para ver qué falta.\n", comandos.Citar(prog.Nombre))
+		} else {
+			fmt.Fprintf(salida, "Usa DIAGNOSTICO %s EN %s para ver qué falta.\n", comandos.Citar(prog.Nombre), plataforma)
+		}
+		return resultadoNoEjecutable.de(prog.Nombre, plataforma)
+	}
+	if criterio == "BARATA" {
+		fmt.Fprintf(salida, "El programa '%s' puede ser ejecutado en %s siguiendo la ruta: %s (costo %g)\n", prog.Nombre, plataforma, strings.Join(ruta.Lenguajes, " -> "), ruta.Costo)
 	} else {
-		fmt.Printf("No se encontró una ruta para ejecutar el programa '%s' en LOCAL.\n", prog.Nombre)
+		fmt.Fprintf(salida, "El programa '%s' puede ser ejecutado en %s siguiendo la ruta: %s\n", prog.Nombre, plataforma, strings.Join(ruta.Lenguajes, " -> "))
+	}
+	for i, herramienta := range ruta.Herramientas {
+		if ruta.CorreEn[i] != "" {
+			fmt.Fprintf(salida, "  Compilación cruzada: %s\n", herramienta)
+		}
 	}
+	return resultadoOK.de(prog.Nombre, plataforma)
 }
 
-// Verifica si el lenguaje se puede ejecutar directamente en LOCAL o con algún intérprete
-func puedeSerEjecutado(lenguaje string) bool {
-	if lenguaje == LENGUAJE_LOCAL {
-		return true
+// Lista todas las rutas simples del programa hasta la plataforma, con orden y límites opcionales
+func handleRutas(salida io.Writer, tc *toolchain.Toolchain, args []string, plataforma string) resultado {
+	if len(args) < 1 || len(args) > 4 {
+		fmt.Fprintln(salida, "Error: Uso: RUTAS <nombre> [LARGO|COSTO] [max_rutas] [max_profundidad] [EN <plataforma>]")
+		return resultadoError
+	}
+	plataforma, ok := leerPlataforma(salida, tc, plataforma)
+	if !ok {
+		return resultadoError
+	}
+	prog, ok := tc.Programa(args[0])
+	if !ok {
+		fmt.Fprintf(salida, "Error: Programa '%s' no definido.\n", args[0])
+		return resultadoError
+	}
+
+	opciones := toolchain.OpcionesRutas{Orden: toolchain.PorLargo, Plataforma: plataforma}
+	if len(args) > 1 {
+		switch strings.ToUpper(args[1]) {
+		case "LARGO":
+		case "COSTO":
+			opciones.Orden = toolchain.PorCosto
+		default:
+			fmt.Fprintln(salida, "Error: El orden de las rutas debe ser LARGO o COSTO.")
+			return resultadoError
+		}
 	}
-	for _, interp := range interpretes {
-		if interp.LenguajeBase == lenguaje && interp.Lenguaje == LENGUAJE_LOCAL {
-			return true
+	limites := []*int{&opciones.MaxRutas, &opciones.MaxProfundidad}
+	for i := 2; i < len(args); i++ {
+		n, err := strconv.Atoi(args[i])
+		if err != nil || n < 0 {
+			fmt.Fprintln(salida, "Error: Los límites deben ser enteros no negativos.")
+			return resultadoError
 		}
+		*limites[i-2] = n
+	}
+
+	rutas := tc.TodasLasRutas(prog.Lenguaje, opciones)
+	if len(rutas) == 0 {
+		fmt.Fprintf(salida, "No se encontró una ruta para ejecutar el programa '%s' en %s.\n", prog.Nombre, plataforma)
+		return resultadoNoEjecutable.de(prog.Nombre, plataforma)
 	}
-	return false
+	fmt.Fprintf(salida, "Rutas para ejecutar el programa '%s' en %s:\n", prog.Nombre, plataforma)
+	for i, ruta := range rutas {
+		fmt.Fprintf(salida, "  %d. %s (pasos %d, costo %g)\n", i+1, strings.Join(ruta.Lenguajes, " -> "), len(ruta.Lenguajes)-1, ruta.Costo)
+	}
+	return resultadoOK.de(prog.Nombre, plataforma)
 }
 
-// Busca una ruta para convertir el lenguaje de origen al destino usando intérpretes y traductores
-func encontrarRutaEjecucion(origen, destino string) ([]string, bool) {
-	visited := make(map[string]bool)
-	var path []string
+// Explica por qué un programa no se puede ejecutar en la plataforma y qué definición bastaría
+// para lograrlo
+func handleDiagnostico(salida io.Writer, tc *toolchain.Toolchain, nombrePrograma, plataforma string) resultado {
+	plataforma, ok := leerPlataforma(salida, tc, plataforma)
+	if !ok {
+		return resultadoError
+	}
+	prog, ok := tc.Programa(nombrePrograma)
+	if !ok {
+		fmt.Fprintf(salida, "Error: Programa '%s' no definido.\n", nombrePrograma)
+		return resultadoError
+	}
+	diag := tc.Diagnosticar(prog.Lenguaje, plataforma)
+	if diag.Ejecutable {
+		fmt.Fprintf(salida, "El programa '%s' ya puede ser ejecutado en %s.\n", prog.Nombre, plataforma)
+		return resultadoOK
+	}
 
-	return dfsEjecucion(origen, destino, path, visited)
+	fmt.Fprintf(salida, "Diagnóstico del programa '%s' (lenguaje: %s) en %s:\n", prog.Nombre, prog.Lenguaje, plataforma)
+	fmt.Fprintf(salida, "  Lenguajes alcanzables: %s\n", strings.Join(diag.Alcanzables, ", "))
+	if len(diag.Bloqueadas) > 0 {
+		fmt.Fprintln(salida, "  Herramientas bloqueadas:")
+		for _, b := range diag.Bloqueadas {
+			fmt.Fprintf(salida, "    %s (%s no es ejecutable)\n", b.Descripcion, b.LenguajeFaltante)
+		}
+	}
+	fmt.Fprintln(salida, "  Basta con definir cualquiera de:")
+	for _, f := range diag.Faltantes {
+		fmt.Fprintf(salida, "    DEFINIR INTERPRETE %s %s\n", f.EspecificacionBase(), f.Lenguaje)
+	}
+	return resultadoOK
 }
 
-// DFS para encontrar una ruta de ejecución entre lenguajes
-func dfsEjecucion(actual, destino string, path []string, visited map[string]bool) ([]string, bool) {
-	path = append(path, actual)
-	visited[actual] = true
-
-	if actual == destino {
-		return path, true
+// Muestra lo definido en el catálogo; sin argumentos muestra todas las secciones
+func handleListar(salida io.Writer, tc *toolchain.Toolchain, args []string) resultado {
+	secciones := []string{"PROGRAMAS", "INTERPRETES", "TRADUCTORES", "LENGUAJES", "PLATAFORMAS"}
+	if len(args) == 1 {
+		seccion := strings.ToUpper(args[0])
+		if !slices.Contains(secciones, seccion) {
+			fmt.Fprintln(salida, "Error: Se puede listar PROGRAMAS, INTERPRETES, TRADUCTORES, LENGUAJES o PLATAFORMAS.")
+			return resultadoError
+		}
+		secciones = []string{seccion}
 	}
 
-	// Busca intérpretes que puedan transformar el lenguaje actual
-	for _, interp := range interpretes {
-		if interp.LenguajeBase == actual && !visited[interp.Lenguaje] {
-			if newPath, found := dfsEjecucion(interp.Lenguaje, destino, path, visited); found {
-				return newPath, true
+	for _, seccion := range secciones {
+		var lineas []string
+		switch seccion {
+		case "PROGRAMAS":
+			fmt.Fprintln(salida, "Programas:")
+			for _, prog := range tc.Programas() {
+				lineas = append(lineas, fmt.Sprintf("%s (lenguaje: %s)", prog.Nombre, prog.Lenguaje))
+			}
+		case "INTERPRETES":
+			fmt.Fprintln(salida, "Intérpretes:")
+			for _, interp := range tc.Interpretes() {
+				lineas = append(lineas, describirHerramienta(interp.String(), interp.Costo, tc.InterpreteUsable(interp)))
+			}
+		case "TRADUCTORES":
+			fmt.Fprintln(salida, "Traductores:")
+			for _, trad := range tc.Traductores() {
+				lineas = append(lineas, describirHerramienta(trad.String(), trad.Costo, tc.TraductorUsable(trad)))
+			}
+		case "LENGUAJES":
+			fmt.Fprintln(salida, "Lenguajes:")
+			for _, lenguaje := range tc.Lenguajes() {
+				if tc.EsEjecutable(lenguaje) {
+					lineas = append(lineas, lenguaje+" (ejecutable)")
+				} else {
+					lineas = append(lineas, lenguaje)
+				}
 			}
+		case "PLATAFORMAS":
+			fmt.Fprintln(salida, "Plataformas:")
+			lineas = tc.Plataformas()
+		}
+		if len(lineas) == 0 {
+			lineas = []string{"(ninguno)"}
+		}
+		for _, linea := range lineas {
+			fmt.Fprintf(salida, "  %s\n", linea)
+		}
+	}
+	return resultadoOK
+}
+
+// Da el texto de una herramienta con su costo y si se puede usar
+func describirHerramienta(nombre string, costo float64, usable bool) string {
+	if usable {
+		return fmt.Sprintf("%s (costo %g)", nombre, costo)
+	}
+	return fmt.Sprintf("%s (costo %g, no se puede usar)", nombre, costo)
+}
+
+// Muestra los programas y las herramientas que entran, salen o están escritas en un lenguaje;
+// si se ejecuta y qué herramientas se pueden usar depende de la plataforma
+func handleDescribir(salida io.Writer, tc *toolchain.Toolchain, lenguaje, plataforma string) resultado {
+	plataforma, ok := leerPlataforma(salida, tc, plataforma)
+	if !ok {
+		return resultadoError
+	}
+	desc := tc.Describir(lenguaje, plataforma)
+	// Un lenguaje que solo aparece con rango, como Python>=3.8, se conoce por sus herramientas
+	if !slices.Contains(tc.Lenguajes(), desc.Lenguaje) && len(desc.InterpretesDe) == 0 && len(desc.TraductoresDesde) == 0 {
+		fmt.Fprintf(salida, "Error: El lenguaje '%s' no aparece en el catálogo.\n", lenguaje)
+		return resultadoError
+	}
+	lenguaje = desc.Lenguaje
+	if desc.Ejecutable {
+		fmt.Fprintf(salida, "Lenguaje %s (ejecutable en %s):\n", lenguaje, plataforma)
+	} else {
+		fmt.Fprintf(salida, "Lenguaje %s (no ejecutable en %s):\n", lenguaje, plataforma)
+	}
+	if len(desc.Versiones) > 0 {
+		fmt.Fprintf(salida, "  Versiones ejecutables en %s: %s\n", plataforma, strings.Join(desc.Versiones, ", "))
+	}
+
+	if len(desc.Programas) > 0 {
+		var nombres []string
+		for _, prog := range desc.Programas {
+			nombres = append(nombres, prog.Nombre)
+		}
+		fmt.Fprintf(salida, "  Programas: %s\n", strings.Join(nombres, ", "))
+	}
+	mostrarInterpretes := func(titulo string, lista []toolchain.HerramientaUsable[toolchain.Interprete]) {
+		if len(lista) == 0 {
+			return
+		}
+		fmt.Fprintf(salida, "  %s:\n", titulo)
+		for _, h := range lista {
+			fmt.Fprintf(salida, "    %s\n", describirHerramienta(h.Herramienta.String(), h.Herramienta.Costo, h.Usable))
+		}
+	}
+	mostrarTraductores := func(titulo string, lista []toolchain.HerramientaUsable[toolchain.Traductor]) {
+		if len(lista) == 0 {
+			return
+		}
+		fmt.Fprintf(salida, "  %s:\n", titulo)
+		for _, h := range lista {
+			fmt.Fprintf(salida, "    %s\n", describirHerramienta(h.Herramienta.String(), h.Herramienta.Costo, h.Usable))
+		}
+	}
+	mostrarInterpretes("Intérpretes que lo ejecutan", desc.InterpretesDe)
+	mostrarTraductores("Traductores que salen de él", desc.TraductoresDesde)
+	mostrarTraductores("Traductores que llegan a él", desc.TraductoresHacia)
+	mostrarInterpretes("Intérpretes escritos en él", desc.InterpretesEn)
+	mostrarTraductores("Traductores escritos en él", desc.TraductoresEn)
+	return resultadoOK
+}
+
+// Lista todos los programas que se pueden ejecutar en la plataforma en este momento
+func handleEjecutables(salida io.Writer, tc *toolchain.Toolchain, plataforma string) resultado {
+	plataforma, ok := leerPlataforma(salida, tc, plataforma)
+	if !ok {
+		return resultadoError
+	}
+	programas := tc.ProgramasEjecutables(plataforma)
+	if len(programas) == 0 {
+		fmt.Fprintf(salida, "Ningún programa se puede ejecutar en %s.\n", plataforma)
+		return resultadoOK
+	}
+	fmt.Fprintf(salida, "Programas ejecutables en %s:\n", plataforma)
+	for _, prog := range programas {
+		fmt.Fprintf(salida, "  %s (lenguaje: %s)\n", prog.Nombre, prog.Lenguaje)
+	}
+	return resultadoOK
+}
+
+// Muestra las herramientas autoalojadas, los ciclos de dependencias y cómo arrancar los
+// que todavía no se pueden ejecutar en la plataforma
+func handleBootstrapping(salida io.Writer, tc *toolchain.Toolchain, plataforma string) resultado {
+	plataforma, ok := leerPlataforma(salida, tc, plataforma)
+	if !ok {
+		return resultadoError
+	}
+	analisis := tc.AnalizarBootstrapping(plataforma)
+	if len(analisis.Autoalojadas) == 0 {
+		fmt.Fprintln(salida, "No hay herramientas autoalojadas.")
+	} else {
+		fmt.Fprintln(salida, "Herramientas autoalojadas:")
+		for _, h := range analisis.Autoalojadas {
+			fmt.Fprintf(salida, "  %s\n", h)
 		}
 	}
 
-	// Busca traductores que puedan transformar el lenguaje actual
-	for _, trad := range traductores {
-		if trad.LenguajeOrigen == actual && !visited[trad.LenguajeDestino] {
-			if newPath, found := dfsEjecucion(trad.LenguajeDestino, destino, path, visited); found {
-				return newPath, true
+	if len(analisis.Ciclos) == 0 {
+		fmt.Fprintln(salida, "No hay ciclos de dependencias.")
+		return resultadoOK
+	}
+	fmt.Fprintln(salida, "Ciclos de dependencias:")
+	for _, ciclo := range analisis.Ciclos {
+		if ciclo.Resuelto {
+			fmt.Fprintf(salida, "  %s (resuelto)\n", strings.Join(ciclo.Lenguajes, ", "))
+			continue
+		}
+		fmt.Fprintf(salida, "  %s (sin resolver)\n", strings.Join(ciclo.Lenguajes, ", "))
+		fmt.Fprintln(salida, "    Semillas:")
+		for _, semilla := range ciclo.Semillas {
+			fmt.Fprintf(salida, "      DEFINIR INTERPRETE %s %s\n", semilla, plataforma)
+		}
+		for i, etapa := range ciclo.Etapas {
+			fmt.Fprintf(salida, "    Etapa %d:\n", i)
+			for _, paso := range etapa {
+				fmt.Fprintf(salida, "      %s con el %s\n", paso.Lenguaje, paso.Herramienta)
 			}
 		}
 	}
+	return resultadoOK
+}
+
+// Exporta el grafo del catálogo en DOT o Mermaid, a un archivo o a la salida si el archivo es -.
+// Si se da un programa ejecutable se resalta su ruta más corta hasta la plataforma.
+func handleExportar(salida io.Writer, tc *toolchain.Toolchain, args []string, plataforma string) resultado {
+	if len(args) != 2 && len(args) != 3 {
+		fmt.Fprintln(salida, "Error: Uso: EXPORTAR <DOT|MERMAID> <archivo|-> [programa [EN <plataforma>]]")
+		return resultadoError
+	}
+	plataforma, ok := leerPlataforma(salida, tc, plataforma)
+	if !ok {
+		return resultadoError
+	}
+	formato := strings.ToUpper(args[0])
+	if formato != "DOT" && formato != "MERMAID" {
+		fmt.Fprintln(salida, "Error: El formato debe ser DOT o MERMAID.")
+		return resultadoError
+	}
+
+	var opciones toolchain.OpcionesExportar
+	if len(args) == 3 {
+		prog, ok := tc.Programa(args[2])
+		if !ok {
+			fmt.Fprintf(salida, "Error: Programa '%s' no definido.\n", args[2])
+			return resultadoError
+		}
+		if ruta, ok := tc.RutaMasCortaEn(prog.Lenguaje, plataforma); ok {
+			opciones.Ruta = ruta.Lenguajes
+		} else {
+			fmt.Fprintf(salida, "Aviso: el programa '%s' no tiene ruta a %s, no se resalta nada.\n", prog.Nombre, plataforma)
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if formato == "DOT" {
+		err = tc.ExportarDOT(&buf, opciones)
+	} else {
+		err = tc.ExportarMermaid(&buf, opciones)
+	}
+	if err != nil {
+		fmt.Fprintf(salida, "Error: No se pudo exportar el grafo: %v\n", err)
+		return resultadoError
+	}
+
+	if args[1] == "-" {
+		salida.Write(buf.Bytes())
+		return resultadoOK
+	}
+	if err := os.WriteFile(args[1], buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(salida, "Error: No se pudo exportar a '%s': %v\n", args[1], err)
+		return resultadoError
+	}
+	fmt.Fprintf(salida, "Grafo exportado en formato %s a '%s'.\n", formato, args[1])
+	return resultadoOK
+}
+
+// Guarda el catálogo actual en un archivo JSON
+func handleGuardar(salida io.Writer, tc *toolchain.Toolchain, ruta string) resultado {
+	if err := tc.GuardarArchivo(ruta); err != nil {
+		fmt.Fprintf(salida, "Error: No se pudo guardar '%s': %v\n", ruta, err)
+		return resultadoError
+	}
+	fmt.Fprintf(salida, "Catálogo guardado en '%s'.\n", ruta)
+	return resultadoOK
+}
+
+// Reemplaza el catálogo actual por el del archivo; si el archivo es inválido no cambia nada
+func handleCargar(salida io.Writer, tc *toolchain.Toolchain, ruta string) resultado {
+	cargado, err := toolchain.CargarArchivo(ruta)
+	if err != nil {
+		fmt.Fprintf(salida, "Error: No se pudo cargar '%s': %v\n", ruta, err)
+		return resultadoError
+	}
+	if err := mismaNormalizacion(cargado, tc.Normalizacion()); err != nil {
+		fmt.Fprintf(salida, "Error: No se pudo cargar '%s': %v\n", ruta, err)
+		return resultadoError
+	}
+	*tc = *cargado
+	fmt.Fprintf(salida, "Catálogo cargado desde '%s'.\n", ruta)
+	return resultadoOK
+}
+
+// mismaNormalizacion da error si el catálogo cargado compara los nombres de otra forma que la
+// sesión. No se convierte porque con la nueva política dos lenguajes podrían pasar a ser uno.
+func mismaNormalizacion(cargado *toolchain.Toolchain, sesion toolchain.Normalizacion) error {
+	if cargado.Normalizacion() != sesion {
+		return fmt.Errorf("el catálogo usa nombres %s y la sesión %s", cargado.Normalizacion(), sesion)
+	}
+	return nil
+}
 
-	// Si no hay forma, regresa nil
-	return nil, false
+// flagDado dice si la opción se escribió en la línea de comandos en vez de tomar su valor por defecto
+func flagDado(nombre string) bool {
+	dado := false
+	flag.Visit(func(f *flag.Flag) {
+		dado = dado || f.Name == nombre
+	})
+	return dado
 }
@@ -2,65 +2,81 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"pregunta5/toolchain"
 )
 
-// Esta función reinicia los mapas globales antes de cada test
-func resetGlobalState() {
-	programas = make(map[string]Programa)
-	interpretes = make(map[string]Interprete)
-	traductores = make(map[string]Traductor)
+// handleDefinir procesa DEFINIR con el tipo y sus argumentos, igual que si se escribiera en el REPL
+func handleDefinir(salida io.Writer, tc *toolchain.Toolchain, args []string) resultado {
+	return procesarPalabras(salida, comandosSimulador(salida, tc), append([]string{"DEFINIR"}, args...))
+}
+
+// handleEliminar procesa ELIMINAR con el tipo y sus argumentos, igual que si se escribiera en el REPL
+func handleEliminar(salida io.Writer, tc *toolchain.Toolchain, args []string) resultado {
+	return procesarPalabras(salida, comandosSimulador(salida, tc), append([]string{"ELIMINAR"}, args...))
 }
 
 // Prueba para definir un programa y verificar que se guarda bien
 func TestDefinirPrograma(t *testing.T) {
-	resetGlobalState()
-	handleDefinir([]string{"PROGRAMA", "miApp", "GO"})
-	if _, ok := programas["miApp"]; !ok {
+	tc := toolchain.New()
+	handleDefinir(io.Discard, tc, []string{"PROGRAMA", "miApp", "GO"})
+	if _, ok := tc.Programa("miApp"); !ok {
 		t.Errorf("Programa 'miApp' no fue definido correctamente.")
 	}
 }
 
-// Prueba para definir un intérprete y ver que se guarda en el mapa
+// Prueba para definir un intérprete y ver que se guarda en el catálogo
 func TestDefinirInterprete(t *testing.T) {
-	resetGlobalState()
-	handleDefinir([]string{"INTERPRETE", "GO", "LOCAL"})
-	if _, ok := interpretes["GO"]; !ok {
+	tc := toolchain.New()
+	handleDefinir(io.Discard, tc, []string{"INTERPRETE", "GO", "LOCAL"})
+	if !tc.EsEjecutable("GO") {
 		t.Errorf("Intérprete de GO no fue definido correctamente.")
 	}
 }
 
-// Prueba para definir un traductor y checar que la clave esté en el mapa
+// Prueba para definir un traductor y checar que quede en el catálogo
 func TestDefinirTraductor(t *testing.T) {
-	resetGlobalState()
-	handleDefinir([]string{"TRADUCTOR", "COMPILADOR_C", "C", "BINARIO"})
-	key := fmt.Sprintf("%s-%s-%s", "COMPILADOR_C", "C", "BINARIO")
-	if _, ok := traductores[key]; !ok {
+	tc := toolchain.New()
+	handleDefinir(io.Discard, tc, []string{"TRADUCTOR", "COMPILADOR_C", "C", "BINARIO"})
+	traductores := tc.Traductores()
+	if len(traductores) != 1 || traductores[0] != (toolchain.Traductor{LenguajeBase: "COMPILADOR_C", LenguajeOrigen: "C", LenguajeDestino: "BINARIO", Costo: toolchain.COSTO_POR_DEFECTO}) {
 		t.Errorf("Traductor de C a BINARIO no fue definido correctamente.")
 	}
 }
 
+// Prueba que los duplicados se avisan en vez de guardarse dos veces
+func TestDefinirDuplicados(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	handleDefinir(&out, tc, []string{"INTERPRETE", "C", "Java"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "C", "Java"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "C", "Java", "C"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "C", "Java", "C"})
+
+	if !strings.Contains(out.String(), "Aviso: el intérprete de C en Java ya estaba definido.") {
+		t.Errorf("No se avisó del intérprete duplicado. Output: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "Aviso: el traductor de C de Java a C ya estaba definido.") {
+		t.Errorf("No se avisó del traductor duplicado. Output: %s", out.String())
+	}
+}
+
 // Prueba para cuando no hay ruta para ejecutar un programa
 func TestHandleEjecutable_NoRouteFound(t *testing.T) {
-	resetGlobalState()
-	programas["unreachableProg"] = Programa{Nombre: "unreachableProg", Lenguaje: "FANTASY_LANG"}
+	tc := toolchain.New()
+	tc.DefinirPrograma("unreachableProg", "FANTASY_LANG")
 
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-	defer func() {
-		os.Stdout = oldStdout
-	}()
-
-	handleEjecutable("unreachableProg")
-
-	w.Close()
-	out, _ := io.ReadAll(r)
-	output := string(out)
+	var out bytes.Buffer
+	handleEjecutable(&out, tc, "unreachableProg", "CORTA", toolchain.LENGUAJE_LOCAL)
+	output := out.String()
 
 	if !strings.Contains(output, "No se encontró una ruta para ejecutar el programa 'unreachableProg' en LOCAL.") {
 		t.Errorf("Esperaba mensaje de 'no se encontró ruta' para un programa inalcanzable, obtuve: %s", output)
@@ -69,11 +85,8 @@ func TestHandleEjecutable_NoRouteFound(t *testing.T) {
 
 // Prueba para simular la entrada por stdin y checar varios comandos
 func TestMainInputHandling(t *testing.T) {
-	resetGlobalState()
-
-	interpretes["GO"] = Interprete{LenguajeBase: "GO", Lenguaje: LENGUAJE_LOCAL}
-
-	input := `DEFINIR PROGRAMA miApp GO
+	input := `DEFINIR INTERPRETE GO LOCAL
+DEFINIR PROGRAMA miApp GO
 EJECUTABLE miApp
 EJECUTABLE
 UNKNOWN_COMMAND
@@ -109,10 +122,10 @@ SALIR`
 	if !strings.Contains(output, "El programa 'miApp' puede ser ejecutado en LOCAL siguiendo la ruta: GO -> LOCAL") {
 		t.Errorf("Salida EJECUTABLE miApp incorrecta. Output: %s", output)
 	}
-	if !strings.Contains(output, "Error: Uso EJECUTABLE <nombre>") {
+	if !strings.Contains(output, "Error: Uso: EJECUTABLE <nombre>") {
 		t.Errorf("No se manejó el error de EJECUTABLE sin nombre. Output: %s", output)
 	}
-	if !strings.Contains(output, "Comando desconocido. Por favor, usa DEFINIR, EJECUTABLE o SALIR.") {
+	if !strings.Contains(output, "Comando desconocido. Usa AYUDA para ver los comandos.") {
 		t.Errorf("No se manejó el comando desconocido. Output: %s", output)
 	}
 	if !strings.Contains(output, "Saliendo del simulador.") {
@@ -120,147 +133,678 @@ SALIR`
 	}
 }
 
+// Prueba que el REPL termina al acabarse la entrada aunque no haya SALIR
+func TestREPLFinDeEntrada(t *testing.T) {
+	var out bytes.Buffer
+	ejecutarREPL(strings.NewReader("DEFINIR PROGRAMA a GO\n"), &out, toolchain.New())
+	if !strings.Contains(out.String(), "Programa 'a' en GO definido.") {
+		t.Errorf("No se procesó la entrada. Output: %s", out.String())
+	}
+}
+
 // Prueba para checar los errores al definir cosas mal
 func TestDefinirErrores(t *testing.T) {
-	resetGlobalState()
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	tc := toolchain.New()
+	casos := []struct {
+		args     []string
+		esperado string
+	}{
+		{[]string{}, "Error: Uso: DEFINIR <tipo> [argumentos]"},
+		{[]string{"PROGRAMA", "nombre"}, "Error: Uso: DEFINIR PROGRAMA <nombre> <lenguaje>"},
+		{[]string{"INTERPRETE", "GO"}, "Error: Uso: DEFINIR INTERPRETE <lenguaje_base> <lenguaje>"},
+		{[]string{"TRADUCTOR", "C++", "C"}, "Error: Uso: DEFINIR TRADUCTOR <lenguaje_base> <lenguaje_origen> <lenguaje_destino>"},
+		{[]string{"INTERPRETE", "GO", "LOCAL", "rapido"}, "Error: El costo debe ser un número no negativo."},
+		{[]string{"OTROTIPO", "algo", "mas"}, "Error: DEFINIR no acepta 'OTROTIPO', usa PROGRAMA, INTERPRETE, TRADUCTOR o PLATAFORMA."},
+	}
+	for _, caso := range casos {
+		var out bytes.Buffer
+		handleDefinir(&out, tc, caso.args)
+		if !strings.Contains(out.String(), caso.esperado) {
+			t.Errorf("Esperaba '%s' para %v, obtuve: %s", caso.esperado, caso.args, out.String())
+		}
+	}
+}
 
-	handleDefinir([]string{})
-	w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stdout = old
-	if !strings.Contains(string(out), "Error: Uso DEFINIR <tipo> [argumentos]") {
-		t.Errorf("Esperaba error para DEFINIR sin argumentos, obtuve: %s", string(out))
+// Prueba para checar que handleEjecutable imprime lo correcto
+func TestHandleEjecutable(t *testing.T) {
+	tc := toolchain.New()
+	tc.DefinirPrograma("testProg", "GO")
+	tc.DefinirInterprete("GO", toolchain.LENGUAJE_LOCAL)
+
+	var out bytes.Buffer
+	handleEjecutable(&out, tc, "testProg", "CORTA", toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "El programa 'testProg' puede ser ejecutado en LOCAL siguiendo la ruta: GO -> LOCAL") {
+		t.Errorf("Salida de EJECUTABLE incorrecta. Obtenido: %s", out.String())
 	}
 
-	resetOutput := func() {
-		old = os.Stdout
-		r, w, _ = os.Pipe()
-		os.Stdout = w
+	out.Reset()
+	handleEjecutable(&out, tc, "nonExistentProg", "CORTA", toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "Error: Programa 'nonExistentProg' no definido.") {
+		t.Errorf("Esperaba error para programa no existente, obtuve: %s", out.String())
 	}
-	captureOutput := func() string {
-		w.Close()
-		out, _ := io.ReadAll(r)
-		os.Stdout = old
-		return string(out)
+}
+
+// Prueba las opciones de ruta CORTA y BARATA del comando EJECUTABLE
+func TestHandleEjecutableCriterios(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	handleDefinir(&out, tc, []string{"PROGRAMA", "app", "PY"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "PY", "LOCAL", "50"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "C", "LOCAL"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "C", "PY", "C", "5"})
+
+	out.Reset()
+	handleEjecutable(&out, tc, "app", "CORTA", toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "siguiendo la ruta: PY -> LOCAL\n") {
+		t.Errorf("La ruta CORTA debería ser PY -> LOCAL. Obtenido: %s", out.String())
 	}
 
-	resetOutput()
-	handleDefinir([]string{})
-	if outStr := captureOutput(); !strings.Contains(outStr, "Error: Uso DEFINIR <tipo> [argumentos]") {
-		t.Errorf("Esperaba error para DEFINIR sin argumentos, obtuve: %s", outStr)
+	out.Reset()
+	handleEjecutable(&out, tc, "app", "BARATA", toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "siguiendo la ruta: PY -> C -> LOCAL (costo 6)") {
+		t.Errorf("La ruta BARATA debería pasar por C. Obtenido: %s", out.String())
 	}
 
-	resetOutput()
-	handleDefinir([]string{"PROGRAMA", "nombre"})
-	if outStr := captureOutput(); !strings.Contains(outStr, "Error: Uso DEFINIR PROGRAMA <nombre> <lenguaje>") {
-		t.Errorf("Esperaba error para DEFINIR PROGRAMA sin lenguaje, obtuve: %s", outStr)
+	out.Reset()
+	handleEjecutable(&out, tc, "app", "RAPIDA", toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "Error: El criterio de ruta debe ser CORTA o BARATA.") {
+		t.Errorf("No se rechazó el criterio desconocido. Obtenido: %s", out.String())
 	}
+}
 
-	resetOutput()
-	handleDefinir([]string{"INTERPRETE", "GO"})
-	if outStr := captureOutput(); !strings.Contains(outStr, "Error: Uso DEFINIR INTERPRETE <lenguaje_base> <lenguaje>") {
-		t.Errorf("Esperaba error para DEFINIR INTERPRETE con pocos argumentos, obtuve: %s", outStr)
+// Prueba DEFINIR PLATAFORMA y EJECUTABLE ... EN <plataforma> con compilación cruzada
+func TestPlataformas(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	tabla := comandosSimulador(&out, tc)
+	for _, linea := range []string{
+		"DEFINIR PLATAFORMA X86",
+		"DEFINIR PLATAFORMA ARM",
+		"DEFINIR PROGRAMA app C",
+		"DEFINIR INTERPRETE GO X86",
+		"DEFINIR TRADUCTOR GO C ARM",
+	} {
+		procesarLinea(&out, tabla, linea)
+	}
+	if !strings.Contains(out.String(), "Plataforma X86 definida.\n") {
+		t.Errorf("No se confirmó la plataforma. Obtenido: %s", out.String())
 	}
 
-	resetOutput()
-	handleDefinir([]string{"TRADUCTOR", "C++", "C"})
-	if outStr := captureOutput(); !strings.Contains(outStr, "Error: Uso DEFINIR TRADUCTOR <lenguaje_base> <lenguaje_origen> <lenguaje_destino>") {
-		t.Errorf("Esperaba error para DEFINIR TRADUCTOR con pocos argumentos, obtuve: %s", outStr)
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "EJECUTABLE app BARATA EN ARM"); res != resultadoOK.de("app", "ARM") {
+		t.Errorf("app debería ser ejecutable en ARM. Obtenido: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "puede ser ejecutado en ARM siguiendo la ruta: C -> ARM (costo 1)\n") ||
+		!strings.Contains(out.String(), "  Compilación cruzada: traductor de GO de C a ARM (corre en X86)\n") {
+		t.Errorf("Salida de EJECUTABLE EN ARM incorrecta: %s", out.String())
 	}
 
-	resetOutput()
-	handleDefinir([]string{"OTROTIPO", "algo", "mas"})
-	if outStr := captureOutput(); !strings.Contains(outStr, "Error: Tipo de definición desconocido. Usa PROGRAMA, INTERPRETE o TRADUCTOR.") {
-		t.Errorf("Esperaba error para tipo de definición desconocido, obtuve: %s", outStr)
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "EJECUTABLE app"); res != resultadoNoEjecutable.de("app", toolchain.LENGUAJE_LOCAL) {
+		t.Errorf("app no debería ser ejecutable en LOCAL. Obtenido: %s", out.String())
+	}
+
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "EJECUTABLE app en MIPS"); res != resultadoError || out.String() != "Error: Plataforma 'MIPS' no definida.\n" {
+		t.Errorf("No se rechazó la plataforma desconocida. Obtenido: %s", out.String())
+	}
+
+	out.Reset()
+	procesarLinea(&out, tabla, "DEFINIR PLATAFORMA ARM")
+	if out.String() != "Aviso: la plataforma ARM ya estaba definida.\n" {
+		t.Errorf("No se avisó de la plataforma repetida. Obtenido: %s", out.String())
+	}
+
+	out.Reset()
+	handleListar(&out, tc, []string{"plataformas"})
+	if out.String() != "Plataformas:\n  LOCAL\n  X86\n  ARM\n" {
+		t.Errorf("LISTAR PLATAFORMAS incorrecto:\n%s", out.String())
 	}
 }
 
-// Prueba para ver si puedeSerEjecutado funciona bien
-func TestPuedeSerEjecutado(t *testing.T) {
-	resetGlobalState()
-	if !puedeSerEjecutado(LENGUAJE_LOCAL) {
-		t.Errorf("LOCAL debería ser siempre ejecutable.")
+// Prueba que los nombres no distinguen mayúsculas y el comando ALIAS
+func TestAlias(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	tabla := comandosSimulador(&out, tc)
+	if procesarLinea(&out, tabla, "ALIAS"); out.String() != "No hay alias definidos.\n" {
+		t.Errorf("ALIAS sin alias incorrecto: %s", out.String())
+	}
+	for _, linea := range []string{
+		"DEFINIR PROGRAMA foo python",
+		"DEFINIR INTERPRETE Python LOCAL",
+		"ALIAS py Python",
+		"DEFINIR PROGRAMA bar PY",
+	} {
+		procesarLinea(&out, tabla, linea)
 	}
-	if puedeSerEjecutado("GO") {
-		t.Errorf("GO no debería ser ejecutable sin un intérprete.")
+	if !strings.Contains(out.String(), "Alias py definido para Python.\n") {
+		t.Errorf("No se confirmó el alias. Obtenido: %s", out.String())
 	}
-	interpretes["GO"] = Interprete{LenguajeBase: "GO", Lenguaje: LENGUAJE_LOCAL}
-	if !puedeSerEjecutado("GO") {
-		t.Errorf("GO debería ser ejecutable con un intérprete GO -> LOCAL.")
+
+	for _, nombre := range []string{"foo", "bar"} {
+		out.Reset()
+		if res := procesarLinea(&out, tabla, "EJECUTABLE "+nombre); res != resultadoOK.de(nombre, toolchain.LENGUAJE_LOCAL) || !strings.Contains(out.String(), "la ruta: Python -> LOCAL\n") {
+			t.Errorf("%s debería ser ejecutable por Python. Obtenido: %s", nombre, out.String())
+		}
+	}
+
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "ALIAS PY Ruby"); res != resultadoError || out.String() != "Error: el alias py ya estaba definido para Python.\n" {
+		t.Errorf("No se rechazó el alias repetido. Obtenido: %s", out.String())
+	}
+
+	out.Reset()
+	procesarLinea(&out, tabla, "ALIAS")
+	if out.String() != "Alias:\n  py = Python\n" {
+		t.Errorf("Lista de alias incorrecta: %s", out.String())
 	}
 }
 
-// Prueba para checar que encontrarRutaEjecucion encuentra la ruta correcta
-func TestEncontrarRutaEjecucion(t *testing.T) {
-	resetGlobalState()
+// Prueba las versiones en los comandos: rangos al definir, la ruta con versiones concretas y
+// los errores de versión
+func TestVersiones(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	tabla := comandosSimulador(&out, tc)
+	for _, linea := range []string{
+		"DEFINIR PROGRAMA nuevo Python@3.11",
+		"DEFINIR PROGRAMA viejo Python@2.7",
+		"DEFINIR INTERPRETE Python>=3.8,<4 C@11",
+		"DEFINIR INTERPRETE C@11 LOCAL",
+	} {
+		if res := procesarLinea(&out, tabla, linea); res != resultadoOK {
+			t.Fatalf("%s falló: %s", linea, out.String())
+		}
+	}
 
-	path, found := encontrarRutaEjecucion(LENGUAJE_LOCAL, LENGUAJE_LOCAL)
-	if !found || strings.Join(path, " -> ") != "LOCAL" {
-		t.Errorf("Ruta para LOCAL a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+	out.Reset()
+	procesarLinea(&out, tabla, "EJECUTABLE nuevo")
+	if !strings.Contains(out.String(), "la ruta: Python@3.11 -> C@11 -> LOCAL\n") {
+		t.Errorf("La ruta no muestra las versiones. Obtenido: %s", out.String())
+	}
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "EJECUTABLE viejo"); res != resultadoNoEjecutable.de("viejo", toolchain.LENGUAJE_LOCAL) {
+		t.Errorf("Python 2.7 no cumple el rango del intérprete. Obtenido: %s", out.String())
 	}
 
-	interpretes["GO"] = Interprete{LenguajeBase: "GO", Lenguaje: LENGUAJE_LOCAL}
-	path, found = encontrarRutaEjecucion("GO", LENGUAJE_LOCAL)
-	if !found || strings.Join(path, " -> ") != "GO -> LOCAL" {
-		t.Errorf("Ruta para GO a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "DESCRIBIR Python"); res != resultadoOK || !strings.Contains(out.String(), "intérprete de Python>=3.8,<4 en C@11") {
+		t.Errorf("DESCRIBIR de un lenguaje con rango incorrecto. Obtenido: %s", out.String())
 	}
 
-	interpretes["JS"] = Interprete{LenguajeBase: "JS", Lenguaje: LENGUAJE_LOCAL}
-	traductores[fmt.Sprintf("%s-%s-%s", "TS_COMPILER", "TS", "JS")] = Traductor{
-		LenguajeBase: "TS_COMPILER", LenguajeOrigen: "TS", LenguajeDestino: "JS"}
-	path, found = encontrarRutaEjecucion("TS", LENGUAJE_LOCAL)
-	if !found || strings.Join(path, " -> ") != "TS -> JS -> LOCAL" {
-		t.Errorf("Ruta para TS a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "DEFINIR PROGRAMA malo Python>=3"); res != resultadoError || !strings.HasPrefix(out.String(), "Error: ") {
+		t.Errorf("Un programa con rango debería ser un error. Obtenido: %s", out.String())
+	}
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "DEFINIR INTERPRETE Python>=3.8.0,<4.0 C@11"); res != resultadoOK || !strings.HasPrefix(out.String(), "Aviso: ") {
+		t.Errorf("El mismo rango escrito distinto debería ser un aviso. Obtenido: %s", out.String())
 	}
+}
 
-	interpretes["BINARIO"] = Interprete{LenguajeBase: "BINARIO", Lenguaje: LENGUAJE_LOCAL}
-	traductores[fmt.Sprintf("%s-%s-%s", "PY_TO_C", "PYTHON", "C")] = Traductor{
-		LenguajeBase: "PY_TO_C", LenguajeOrigen: "PYTHON", LenguajeDestino: "C"}
-	traductores[fmt.Sprintf("%s-%s-%s", "C_COMPILER", "C", "BINARIO")] = Traductor{
-		LenguajeBase: "C_COMPILER", LenguajeOrigen: "C", LenguajeDestino: "BINARIO"}
-	path, found = encontrarRutaEjecucion("PYTHON", LENGUAJE_LOCAL)
-	if !found || strings.Join(path, " -> ") != "PYTHON -> C -> BINARIO -> LOCAL" {
-		t.Errorf("Ruta para PYTHON a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+// Prueba que los nombres entre comillas pueden tener espacios y que las comillas sin cerrar
+// son un error
+func TestComillas(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	tabla := comandosSimulador(&out, tc)
+	procesarLinea(&out, tabla, `DEFINIR INTERPRETE GO LOCAL`)
+	if res := procesarLinea(&out, tabla, `DEFINIR PROGRAMA "mi app" GO`); res != resultadoOK {
+		t.Fatalf("No se definió el programa con espacios: %s", out.String())
+	}
+	out.Reset()
+	if res := procesarLinea(&out, tabla, `EJECUTABLE 'mi app' BARATA`); res != resultadoOK.de("mi app", toolchain.LENGUAJE_LOCAL) || !strings.Contains(out.String(), "El programa 'mi app' puede ser ejecutado") {
+		t.Errorf("EJECUTABLE con comillas incorrecto: %s", out.String())
+	}
+	out.Reset()
+	if res := procesarLinea(&out, tabla, `EJECUTABLE "mi app`); res != resultadoError || out.String() != "Error: falta cerrar las comillas \".\n" {
+		t.Errorf("No se rechazaron las comillas sin cerrar: %s", out.String())
+	}
+}
+
+// Prueba AYUDA con y sin comando y que completar sugiere comandos, programas y lenguajes
+func TestAyudaYCompletar(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	tabla := comandosSimulador(&out, tc)
+	procesarLinea(&out, tabla, "DEFINIR PROGRAMA app Python")
+	procesarLinea(&out, tabla, "ALIAS py Python")
+
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "AYUDA eliminar"); res != resultadoOK || strings.Count(out.String(), "  ELIMINAR ") != 3 || strings.Contains(out.String(), "DEFINIR") {
+		t.Errorf("AYUDA ELIMINAR incorrecta:\n%s", out.String())
+	}
+	out.Reset()
+	procesarLinea(&out, tabla, "AYUDA")
+	if !strings.Contains(out.String(), "  RUTAS <nombre> [LARGO|COSTO] [max_rutas] [max_profundidad] [EN <plataforma>]\n      Lista todas las rutas") {
+		t.Errorf("AYUDA no muestra el uso y la descripción:\n%s", out.String())
+	}
+	out.Reset()
+	if res := procesarLinea(&out, tabla, "AYUDA BORRAR"); res != resultadoError || out.String() != "Error: No hay ningún comando BORRAR.\n" {
+		t.Errorf("AYUDA de un comando inexistente incorrecta: %s", out.String())
 	}
 
-	found = false
-	_, found = encontrarRutaEjecucion("UNKNOWN_LANG", LENGUAJE_LOCAL)
-	if found {
-		t.Errorf("Se encontró una ruta para un lenguaje desconocido, lo cual es incorrecto.")
+	casos := []struct {
+		linea      string
+		candidatos []string
+	}{
+		{"ejecutab", []string{"EJECUTABLE", "EJECUTABLES"}},
+		{"DEFINIR i", []string{"INTERPRETE"}},
+		{"EJECUTABLE ", []string{"app"}},
+		{"DEFINIR INTERPRETE p", []string{"Python", "py"}},
+		{"LISTAR pla", []string{"PLATAFORMAS"}},
+		{"AYUDA DEFINIR T", []string{"TRADUCTOR"}},
+	}
+	for _, caso := range casos {
+		if _, candidatos := tabla.Completar(caso.linea); !slices.Equal(candidatos, caso.candidatos) {
+			t.Errorf("Completar(%q) = %v, se esperaba %v", caso.linea, candidatos, caso.candidatos)
+		}
 	}
 }
 
-// Prueba para checar que handleEjecutable imprime lo correcto
-func TestHandleEjecutable(t *testing.T) {
-	resetGlobalState()
-	programas["testProg"] = Programa{Nombre: "testProg", Lenguaje: "GO"}
-	interpretes["GO"] = Interprete{LenguajeBase: "GO", Lenguaje: LENGUAJE_LOCAL}
+// Prueba que RUTAS lista todas las rutas con el orden y los límites pedidos
+func TestHandleRutas(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	handleDefinir(&out, tc, []string{"PROGRAMA", "app", "PY"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "PY", "LOCAL", "50"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "C", "LOCAL"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "C", "PY", "C", "5"})
+
+	out.Reset()
+	handleRutas(&out, tc, []string{"app"}, toolchain.LENGUAJE_LOCAL)
+	esperado := "Rutas para ejecutar el programa 'app' en LOCAL:\n" +
+		"  1. PY -> LOCAL (pasos 1, costo 50)\n" +
+		"  2. PY -> C -> LOCAL (pasos 2, costo 6)\n"
+	if out.String() != esperado {
+		t.Errorf("Salida de RUTAS incorrecta. Obtenido:\n%s", out.String())
+	}
+
+	out.Reset()
+	handleRutas(&out, tc, []string{"app", "COSTO", "1"}, toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "1. PY -> C -> LOCAL") || strings.Contains(out.String(), "2.") {
+		t.Errorf("RUTAS COSTO 1 debería mostrar solo la ruta barata. Obtenido:\n%s", out.String())
+	}
+
+	out.Reset()
+	handleRutas(&out, tc, []string{"app", "LARGO", "0", "1"}, toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "1. PY -> LOCAL") || strings.Contains(out.String(), "2.") {
+		t.Errorf("RUTAS con profundidad 1 debería mostrar solo PY -> LOCAL. Obtenido:\n%s", out.String())
+	}
+
+	casos := [][]string{{}, {"app", "RAPIDO"}, {"app", "COSTO", "-1"}, {"nada"}}
+	for _, args := range casos {
+		out.Reset()
+		handleRutas(&out, tc, args, toolchain.LENGUAJE_LOCAL)
+		if !strings.HasPrefix(out.String(), "Error:") {
+			t.Errorf("Esperaba un error para %v, obtuve: %s", args, out.String())
+		}
+	}
+}
+
+// Prueba que DIAGNOSTICO explica qué falta para ejecutar un programa
+func TestHandleDiagnostico(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	handleDefinir(&out, tc, []string{"PROGRAMA", "app", "TS"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "JS", "LOCAL"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "RUST", "TS", "JS"})
+
+	out.Reset()
+	handleDiagnostico(&out, tc, "app", toolchain.LENGUAJE_LOCAL)
+	esperado := "Diagnóstico del programa 'app' (lenguaje: TS) en LOCAL:\n" +
+		"  Lenguajes alcanzables: TS\n" +
+		"  Herramientas bloqueadas:\n" +
+		"    el traductor de RUST de TS a JS (RUST no es ejecutable)\n" +
+		"  Basta con definir cualquiera de:\n" +
+		"    DEFINIR INTERPRETE RUST LOCAL\n" +
+		"    DEFINIR INTERPRETE TS LOCAL\n"
+	if out.String() != esperado {
+		t.Errorf("Salida de DIAGNOSTICO incorrecta. Obtenido:\n%s", out.String())
+	}
+
+	out.Reset()
+	handleEjecutable(&out, tc, "app", "CORTA", toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "Usa DIAGNOSTICO app para ver qué falta.") {
+		t.Errorf("EJECUTABLE debería sugerir DIAGNOSTICO. Obtenido: %s", out.String())
+	}
+
+	handleDefinir(&out, tc, []string{"INTERPRETE", "RUST", "LOCAL"})
+	out.Reset()
+	handleDiagnostico(&out, tc, "app", toolchain.LENGUAJE_LOCAL)
+	if out.String() != "El programa 'app' ya puede ser ejecutado en LOCAL.\n" {
+		t.Errorf("Salida de DIAGNOSTICO incorrecta para un programa ejecutable: %s", out.String())
+	}
+}
+
+// Prueba que GUARDAR y CARGAR conservan el catálogo y que un archivo inválido o con otra
+// política de nombres no lo borra
+func TestGuardarYCargar(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "catalogo.json")
+	var out bytes.Buffer
+	entrada := "DEFINIR PROGRAMA app GO\nDEFINIR INTERPRETE GO LOCAL\nGUARDAR " + ruta + "\n"
+	ejecutarREPL(strings.NewReader(entrada), &out, toolchain.New())
+	if !strings.Contains(out.String(), "Catálogo guardado en '"+ruta+"'.") {
+		t.Fatalf("No se guardó el catálogo. Output: %s", out.String())
+	}
+
+	tc := toolchain.New()
+	out.Reset()
+	handleCargar(&out, tc, ruta)
+	handleEjecutable(&out, tc, "app", "CORTA", toolchain.LENGUAJE_LOCAL)
+	if !strings.Contains(out.String(), "siguiendo la ruta: GO -> LOCAL") {
+		t.Errorf("El catálogo cargado no tiene las definiciones. Output: %s", out.String())
+	}
 
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	invalido := filepath.Join(t.TempDir(), "invalido.json")
+	os.WriteFile(invalido, []byte("no es json"), 0o644)
+	out.Reset()
+	handleCargar(&out, tc, invalido)
+	if !strings.HasPrefix(out.String(), "Error: No se pudo cargar") {
+		t.Errorf("Esperaba un error al cargar un archivo inválido. Output: %s", out.String())
+	}
+	if _, ok := tc.Programa("app"); !ok {
+		t.Errorf("Un archivo inválido no debería borrar el catálogo actual")
+	}
 
-	handleEjecutable("testProg")
+	// Un catálogo con otra política de nombres no reemplaza al de la sesión
+	exactos := toolchain.New()
+	exactos.UsarNormalizacion(toolchain.NombresExactos)
+	otraPolitica := filepath.Join(t.TempDir(), "exactos.json")
+	if err := exactos.GuardarArchivo(otraPolitica); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	handleCargar(&out, tc, otraPolitica)
+	if out.String() != "Error: No se pudo cargar '"+otraPolitica+"': el catálogo usa nombres exactos y la sesión mayusculas\n" {
+		t.Errorf("Esperaba un error por la política de nombres. Output: %s", out.String())
+	}
+	if _, ok := tc.Programa("app"); !ok || tc.Normalizacion() != toolchain.IgnorarMayusculas {
+		t.Errorf("Un catálogo con otra política no debería cambiar la sesión")
+	}
+}
 
-	w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stdout = old
+// Prueba que el modo script no imprime bienvenida ni prompts y devuelve el código correcto
+func TestEjecutarScript(t *testing.T) {
+	casos := []struct {
+		nombre   string
+		script   string
+		codigo   int
+		esperado string
+	}{
+		{
+			"todas las consultas encuentran ruta",
+			"# Catálogo de prueba\nDEFINIR PROGRAMA app GO\nDEFINIR INTERPRETE GO LOCAL\nEJECUTABLE app\n",
+			0,
+			"Programa 'app' en GO definido.\n" +
+				"Intérprete de GO en LOCAL definido.\n" +
+				"Intentando hacer ejecutable el programa 'app' (lenguaje: GO).\n" +
+				"El programa 'app' puede ser ejecutado en LOCAL siguiendo la ruta: GO -> LOCAL\n",
+		},
+		{
+			"solo cuenta la última consulta de cada programa",
+			"DEFINIR PROGRAMA app GO\nEJECUTABLE app\nDEFINIR INTERPRETE GO LOCAL\nEJECUTABLE app\n",
+			0,
+			"",
+		},
+		{
+			"una consulta final sin ruta",
+			"DEFINIR PROGRAMA app GO\nDEFINIR PROGRAMA otra C\nDEFINIR INTERPRETE GO LOCAL\nEJECUTABLE app\nEJECUTABLE otra\n",
+			1,
+			"",
+		},
+		{
+			"cada plataforma es una consulta aparte",
+			"DEFINIR PLATAFORMA ARM\nDEFINIR PROGRAMA app GO\nDEFINIR INTERPRETE GO LOCAL\nEJECUTABLE app EN ARM\nEJECUTABLE app\n",
+			1,
+			"",
+		},
+		{
+			"la plataforma se reconoce aunque se escriba distinto",
+			"DEFINIR PLATAFORMA ARM\nDEFINIR PROGRAMA app GO\nEJECUTABLE app EN arm\nDEFINIR INTERPRETE GO ARM\nEJECUTABLE app EN ARM\n",
+			0,
+			"",
+		},
+		{
+			"RUTAS sin ruta también cuenta",
+			"DEFINIR PROGRAMA app GO\nRUTAS app\n",
+			1,
+			"",
+		},
+		{
+			"un comando con error",
+			"DEFINIR PROGRAMA app\nEJECUTABLE app\n",
+			2,
+			"",
+		},
+		{
+			"SALIR termina el script",
+			"SALIR\nCOMANDO_INVALIDO\n",
+			0,
+			"Saliendo del simulador.\n",
+		},
+	}
+	for _, caso := range casos {
+		var out bytes.Buffer
+		codigo := ejecutarScript(strings.NewReader(caso.script), &out, toolchain.New())
+		if codigo != caso.codigo {
+			t.Errorf("%s: código %d, esperaba %d. Output: %s", caso.nombre, codigo, caso.codigo, out.String())
+		}
+		if strings.HasPrefix(out.String(), "> ") || strings.Contains(out.String(), "\n> ") || strings.Contains(out.String(), "Simulador de Programas") {
+			t.Errorf("%s: el modo script no debería imprimir bienvenida ni prompts. Output: %s", caso.nombre, out.String())
+		}
+		if caso.esperado != "" && out.String() != caso.esperado {
+			t.Errorf("%s: salida incorrecta:\n%s", caso.nombre, out.String())
+		}
+	}
+}
+
+// Prueba que ELIMINAR avisa qué programas pierden la ruta a LOCAL
+func TestHandleEliminar(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	handleDefinir(&out, tc, []string{"PROGRAMA", "app", "GO"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "GO", "LOCAL"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "GO", "TS", "GO"})
+
+	out.Reset()
+	handleEliminar(&out, tc, []string{"TRADUCTOR", "GO", "TS", "GO"})
+	if out.String() != "Traductor de GO de TS a GO eliminado.\nNingún programa dejó de ser ejecutable.\n" {
+		t.Errorf("Salida de ELIMINAR TRADUCTOR incorrecta: %s", out.String())
+	}
+
+	out.Reset()
+	handleEliminar(&out, tc, []string{"INTERPRETE", "GO", "LOCAL"})
+	esperado := "Intérprete de GO en LOCAL eliminado.\n" +
+		"Programas que ya no se pueden ejecutar:\n" +
+		"  app (lenguaje: GO) en LOCAL\n"
+	if out.String() != esperado {
+		t.Errorf("Salida de ELIMINAR INTERPRETE incorrecta: %s", out.String())
+	}
+
+	out.Reset()
+	handleEliminar(&out, tc, []string{"PROGRAMA", "app"})
+	if out.String() != "Programa 'app' eliminado.\n" {
+		t.Errorf("Salida de ELIMINAR PROGRAMA incorrecta: %s", out.String())
+	}
+
+	casos := []struct {
+		args     []string
+		esperado string
+	}{
+		{[]string{}, "Error: Uso: ELIMINAR <tipo> [argumentos]"},
+		{[]string{"PROGRAMA", "app"}, "Error: el programa 'app' no estaba definido."},
+		{[]string{"INTERPRETE", "GO"}, "Error: Uso: ELIMINAR INTERPRETE <lenguaje_base> <lenguaje>"},
+		{[]string{"TRADUCTOR", "GO", "TS", "GO"}, "Error: el traductor de GO de TS a GO no estaba definido."},
+		{[]string{"OTRO", "x"}, "Error: ELIMINAR no acepta 'OTRO', usa PROGRAMA, INTERPRETE o TRADUCTOR."},
+	}
+	for _, caso := range casos {
+		out.Reset()
+		if handleEliminar(&out, tc, caso.args) != resultadoError || !strings.Contains(out.String(), caso.esperado) {
+			t.Errorf("Esperaba '%s' para %v, obtuve: %s", caso.esperado, caso.args, out.String())
+		}
+	}
+}
+
+// Prueba que EXPORTAR escribe el grafo a la salida o a un archivo
+func TestHandleExportar(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	handleDefinir(&out, tc, []string{"PROGRAMA", "app", "GO"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "GO", "LOCAL"})
+
+	out.Reset()
+	handleExportar(&out, tc, []string{"dot", "-", "app"}, toolchain.LENGUAJE_LOCAL)
+	if !strings.HasPrefix(out.String(), "digraph toolchain {") || !strings.Contains(out.String(), `"GO" -> "LOCAL" [label="intérprete, costo 1", color=red, penwidth=2];`) {
+		t.Errorf("DOT incorrecto: %s", out.String())
+	}
+
+	ruta := filepath.Join(t.TempDir(), "grafo.mmd")
+	out.Reset()
+	handleExportar(&out, tc, []string{"MERMAID", ruta}, toolchain.LENGUAJE_LOCAL)
+	contenido, _ := os.ReadFile(ruta)
+	if out.String() != "Grafo exportado en formato MERMAID a '"+ruta+"'.\n" || !strings.HasPrefix(string(contenido), "graph LR\n") {
+		t.Errorf("No se exportó el archivo Mermaid. Output: %s, contenido: %s", out.String(), contenido)
+	}
+
+	casos := [][]string{{"DOT"}, {"PNG", "-"}, {"DOT", "-", "nada"}}
+	for _, args := range casos {
+		out.Reset()
+		if handleExportar(&out, tc, args, toolchain.LENGUAJE_LOCAL) != resultadoError || !strings.HasPrefix(out.String(), "Error:") {
+			t.Errorf("Esperaba un error para %v, obtuve: %s", args, out.String())
+		}
+	}
+}
+
+// Prueba los comandos de consulta LISTAR, DESCRIBIR y EJECUTABLES
+func TestComandosDeConsulta(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	if handleEjecutables(&out, tc, toolchain.LENGUAJE_LOCAL); out.String() != "Ningún programa se puede ejecutar en LOCAL.\n" {
+		t.Errorf("EJECUTABLES con el catálogo vacío incorrecto: %s", out.String())
+	}
+	handleDefinir(&out, tc, []string{"PROGRAMA", "app", "GO"})
+	handleDefinir(&out, tc, []string{"PROGRAMA", "web", "TS"})
+	handleDefinir(&out, tc, []string{"INTERPRETE", "GO", "LOCAL"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "RUST", "TS", "GO", "3"})
+
+	out.Reset()
+	handleListar(&out, tc, nil)
+	esperado := "Programas:\n" +
+		"  app (lenguaje: GO)\n" +
+		"  web (lenguaje: TS)\n" +
+		"Intérpretes:\n" +
+		"  intérprete de GO en LOCAL (costo 1)\n" +
+		"Traductores:\n" +
+		"  traductor de RUST de TS a GO (costo 3, no se puede usar)\n" +
+		"Lenguajes:\n" +
+		"  GO (ejecutable)\n" +
+		"  LOCAL (ejecutable)\n" +
+		"  RUST\n" +
+		"  TS\n" +
+		"Plataformas:\n" +
+		"  LOCAL\n"
+	if out.String() != esperado {
+		t.Errorf("LISTAR incorrecto:\n%s", out.String())
+	}
+
+	out.Reset()
+	handleListar(&out, tc, []string{"interpretes"})
+	if out.String() != "Intérpretes:\n  intérprete de GO en LOCAL (costo 1)\n" {
+		t.Errorf("LISTAR INTERPRETES incorrecto:\n%s", out.String())
+	}
+
+	out.Reset()
+	handleDescribir(&out, tc, "GO", toolchain.LENGUAJE_LOCAL)
+	esperado = "Lenguaje GO (ejecutable en LOCAL):\n" +
+		"  Programas: app\n" +
+		"  Intérpretes que lo ejecutan:\n" +
+		"    intérprete de GO en LOCAL (costo 1)\n" +
+		"  Traductores que llegan a él:\n" +
+		"    traductor de RUST de TS a GO (costo 3, no se puede usar)\n"
+	if out.String() != esperado {
+		t.Errorf("DESCRIBIR incorrecto:\n%s", out.String())
+	}
+
+	out.Reset()
+	handleEjecutables(&out, tc, toolchain.LENGUAJE_LOCAL)
+	if out.String() != "Programas ejecutables en LOCAL:\n  app (lenguaje: GO)\n" {
+		t.Errorf("EJECUTABLES incorrecto: %s", out.String())
+	}
+
+	out.Reset()
+	if handleListar(&out, tc, []string{"OTRO"}) != resultadoError || handleDescribir(&out, tc, "COBOL", toolchain.LENGUAJE_LOCAL) != resultadoError {
+		t.Errorf("Se esperaban errores para una sección o un lenguaje desconocido: %s", out.String())
+	}
+}
+
+// Prueba un programa que se ejecuta de verdad pasando por un traductor con reglas
+func TestEjecutarPrograma(t *testing.T) {
+	var out bytes.Buffer
+	entrada := `DEFINIR PROGRAMA cuadrado ES
+CODIGO cuadrado leer cuadrado imprimir
+DEFINIR INTERPRETE FORTH LOCAL
+DEFINIR TRADUCTOR FORTH ES FORTH
+IMPLEMENTAR TRADUCTOR FORTH ES FORTH cuadrado=dup,* imprimir=.
+IMPLEMENTAR INTERPRETE FORTH LOCAL
+EJECUTAR cuadrado 12
+`
+	codigo := ejecutarScript(strings.NewReader(entrada), &out, toolchain.New())
+	esperado := "  ES: leer cuadrado imprimir\n" +
+		"  FORTH: leer dup * .\n" +
+		"  LOCAL: leer dup * .\n" +
+		"Salida: 144\n"
+	if codigo != 0 || !strings.HasSuffix(out.String(), esperado) {
+		t.Errorf("EJECUTAR incorrecto (código %d):\n%s", codigo, out.String())
+	}
+
+	casos := []struct {
+		linea    string
+		esperado string
+	}{
+		{"EJECUTAR cuadrado", "Error: error en LOCAL: no hay más entrada."},
+		{"EJECUTAR cuadrado x", "Error: La entrada debe ser una lista de números enteros."},
+		{"CODIGO nada 1", "Error: el programa 'nada' no estaba definido."},
+		{"IMPLEMENTAR TRADUCTOR FORTH ES", "Error: Uso: IMPLEMENTAR"},
+		{"IMPLEMENTAR INTERPRETE FORTH LOCAL sinigual", "Error: La regla 'sinigual' debe tener la forma de=a."},
+		{"IMPLEMENTAR INTERPRETE C LOCAL", "Error: el intérprete de C en LOCAL no estaba definido."},
+		{"IMPLEMENTAR COMPILADOR C LOCAL", "Error: IMPLEMENTAR no acepta 'COMPILADOR', usa INTERPRETE o TRADUCTOR."},
+	}
+	tc := toolchain.New()
+	ejecutarScript(strings.NewReader(entrada), io.Discard, tc)
+	tabla := comandosSimulador(&out, tc)
+	for _, caso := range casos {
+		out.Reset()
+		if procesarLinea(&out, tabla, caso.linea) != resultadoError || !strings.Contains(out.String(), caso.esperado) {
+			t.Errorf("Esperaba '%s' para %q, obtuve: %s", caso.esperado, caso.linea, out.String())
+		}
+	}
+}
 
-	if !strings.Contains(string(out), "El programa 'testProg' puede ser ejecutado en LOCAL siguiendo la ruta: GO -> LOCAL") {
-		t.Errorf("Salida de EJECUTABLE incorrecta. Obtenido: %s", string(out))
+// Prueba el reporte de BOOTSTRAPPING para un compilador autoalojado
+func TestHandleBootstrapping(t *testing.T) {
+	tc := toolchain.New()
+	var out bytes.Buffer
+	if handleBootstrapping(&out, tc, toolchain.LENGUAJE_LOCAL); out.String() != "No hay herramientas autoalojadas.\nNo hay ciclos de dependencias.\n" {
+		t.Errorf("Salida incorrecta con el catálogo vacío: %s", out.String())
 	}
 
-	old = os.Stdout
-	r, w, _ = os.Pipe()
-	os.Stdout = w
-	handleEjecutable("nonExistentProg")
-	w.Close()
-	out, _ = io.ReadAll(r)
-	os.Stdout = old
-	if !strings.Contains(string(out), "Error: Programa 'nonExistentProg' no definido.") {
-		t.Errorf("Esperaba error para programa no existente, obtuve: %s", string(out))
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "X", "X", "LOCAL"})
+	handleDefinir(&out, tc, []string{"TRADUCTOR", "X", "Y", "LOCAL"})
+	out.Reset()
+	handleBootstrapping(&out, tc, toolchain.LENGUAJE_LOCAL)
+	esperado := "Herramientas autoalojadas:\n" +
+		"  traductor de X de X a LOCAL\n" +
+		"Ciclos de dependencias:\n" +
+		"  X (sin resolver)\n" +
+		"    Semillas:\n" +
+		"      DEFINIR INTERPRETE X LOCAL\n" +
+		"    Etapa 0:\n" +
+		"      X con el intérprete de X en LOCAL (semilla)\n" +
+		"    Etapa 1:\n" +
+		"      Y con el traductor de X de Y a LOCAL\n"
+	if out.String() != esperado {
+		t.Errorf("BOOTSTRAPPING incorrecto:\n%s", out.String())
 	}
 }
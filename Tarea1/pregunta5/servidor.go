@@ -0,0 +1,486 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"pregunta5/toolchain"
+)
+
+// ESPACIO_PRINCIPAL es el espacio de trabajo donde queda el catálogo cargado con -cargar
+const ESPACIO_PRINCIPAL = "principal"
+
+// Tamaño máximo del cuerpo de una petición
+const MAX_CUERPO = 1 << 20
+
+// servidor atiende la API HTTP/JSON del simulador. Cada espacio de trabajo tiene su propio
+// catálogo; un espacio se crea al definir algo en él por primera vez. Un solo candado
+// protege a todos los catálogos porque el Toolchain no se puede usar desde varias goroutines.
+type servidor struct {
+	mu            sync.Mutex
+	espacios      map[string]*toolchain.Toolchain
+	normalizacion toolchain.Normalizacion // La que usan los espacios nuevos
+}
+
+// nuevoServidor arma el servidor sin espacios; los nuevos usan la normalización dada
+func nuevoServidor(normalizacion toolchain.Normalizacion) *servidor {
+	return &servidor{espacios: make(map[string]*toolchain.Toolchain), normalizacion: normalizacion}
+}
+
+// rutas devuelve el manejador con todos los endpoints:
+//
+//	GET    /espacios                                    nombres de los espacios
+//	DELETE /espacios/{espacio}                          borra el espacio con su catálogo
+//	GET    /espacios/{espacio}/catalogo                 el catálogo, en el formato de GUARDAR
+//	POST   /espacios/{espacio}/definiciones             define un programa, intérprete, traductor o plataforma
+//	DELETE /espacios/{espacio}/definiciones             elimina un programa, intérprete o traductor
+//	GET    /espacios/{espacio}/programas                los programas, por nombre
+//	GET    /espacios/{espacio}/interpretes              los intérpretes, en el orden en que se definieron
+//	GET    /espacios/{espacio}/traductores              los traductores, en el orden en que se definieron
+//	GET    /espacios/{espacio}/programas/{programa}/ruta?criterio=corta|barata&plataforma=LOCAL
+//	GET    /espacios/{espacio}/grafo?formato=dot|mermaid&programa=nombre&plataforma=LOCAL
+func (s *servidor) rutas() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /espacios", s.listarEspacios)
+	mux.HandleFunc("DELETE /espacios/{espacio}", s.borrarEspacio)
+	mux.HandleFunc("GET /espacios/{espacio}/catalogo", s.catalogo)
+	mux.HandleFunc("POST /espacios/{espacio}/definiciones", s.definir)
+	mux.HandleFunc("DELETE /espacios/{espacio}/definiciones", s.eliminar)
+	mux.HandleFunc("GET /espacios/{espacio}/programas", s.listarProgramas)
+	mux.HandleFunc("GET /espacios/{espacio}/interpretes", s.listarInterpretes)
+	mux.HandleFunc("GET /espacios/{espacio}/traductores", s.listarTraductores)
+	mux.HandleFunc("GET /espacios/{espacio}/programas/{programa}/ruta", s.ruta)
+	mux.HandleFunc("GET /espacios/{espacio}/grafo", s.grafo)
+	return mux
+}
+
+// agregarEspacio pone un catálogo ya armado en un espacio, como el cargado al iniciar
+func (s *servidor) agregarEspacio(nombre string, tc *toolchain.Toolchain) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.espacios[nombre] = tc
+}
+
+// errorHTTP es un error con el código de estado que le corresponde
+type errorHTTP struct {
+	estado  int
+	mensaje string
+}
+
+func (e errorHTTP) Error() string {
+	return e.mensaje
+}
+
+// noEncontrado arma el error 404 de algo que no existe
+func noEncontrado(formato string, args ...any) error {
+	return errorHTTP{http.StatusNotFound, fmt.Sprintf(formato, args...)}
+}
+
+// invalido arma el error 400 de una petición mal escrita
+func invalido(formato string, args ...any) error {
+	return errorHTTP{http.StatusBadRequest, fmt.Sprintf(formato, args...)}
+}
+
+// responder escribe el valor como JSON con el estado dado
+func responder(w http.ResponseWriter, estado int, valor any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(estado)
+	toolchain.NuevoCodificador(w).Encode(valor)
+}
+
+// responderError escribe {"error": "..."} con el estado del error; un error sin estado es
+// una petición inválida. Una definición repetida es un conflicto y una que no estaba, 404.
+func responderError(w http.ResponseWriter, err error) {
+	estado := http.StatusBadRequest
+	var e errorHTTP
+	switch {
+	case errors.As(err, &e):
+		estado = e.estado
+	case toolchain.EsRepetido(err):
+		estado = http.StatusConflict
+	case toolchain.EsNoDefinido(err):
+		estado = http.StatusNotFound
+	}
+	responder(w, estado, map[string]string{"error": err.Error()})
+}
+
+// espacio busca el catálogo del espacio de la petición. Hay que tener el candado.
+func (s *servidor) espacio(r *http.Request) (*toolchain.Toolchain, error) {
+	nombre := r.PathValue("espacio")
+	tc, ok := s.espacios[nombre]
+	if !ok {
+		return nil, noEncontrado("el espacio '%s' no existe", nombre)
+	}
+	return tc, nil
+}
+
+// leerCuerpo decodifica el JSON de la petición sin aceptar campos desconocidos
+func leerCuerpo(w http.ResponseWriter, r *http.Request, destino any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MAX_CUERPO))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(destino); err != nil {
+		return invalido("cuerpo inválido: %v", err)
+	}
+	return nil
+}
+
+func (s *servidor) listarEspacios(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nombres := []string{}
+	for nombre := range s.espacios {
+		nombres = append(nombres, nombre)
+	}
+	slices.Sort(nombres)
+	responder(w, http.StatusOK, map[string][]string{"espacios": nombres})
+}
+
+func (s *servidor) borrarEspacio(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.espacio(r); err != nil {
+		responderError(w, err)
+		return
+	}
+	delete(s.espacios, r.PathValue("espacio"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// catalogo responde con el catálogo en el mismo JSON que escribe GUARDAR
+func (s *servidor) catalogo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, err := s.espacio(r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := tc.Guardar(&buf); err != nil {
+		responderError(w, errorHTTP{http.StatusInternalServerError, err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// definicion es el cuerpo de POST y DELETE en /definiciones. Los campos son los del archivo
+// de catálogo; cuáles hacen falta depende del tipo.
+type definicion struct {
+	Tipo            string   `json:"tipo"` // programa, interprete, traductor o plataforma
+	Nombre          string   `json:"nombre,omitempty"`
+	Lenguaje        string   `json:"lenguaje,omitempty"`
+	LenguajeBase    string   `json:"lenguaje_base,omitempty"`
+	LenguajeOrigen  string   `json:"lenguaje_origen,omitempty"`
+	LenguajeDestino string   `json:"lenguaje_destino,omitempty"`
+	Costo           *float64 `json:"costo,omitempty"`
+}
+
+// faltan revisa que los campos obligatorios del tipo no estén vacíos
+func (d definicion) faltan(campos map[string]string) error {
+	var vacios []string
+	for _, campo := range []string{"nombre", "lenguaje", "lenguaje_base", "lenguaje_origen", "lenguaje_destino"} {
+		if valor, ok := campos[campo]; ok && valor == "" {
+			vacios = append(vacios, campo)
+		}
+	}
+	if len(vacios) > 0 {
+		return invalido("a la definición de tipo %s le falta %s", d.Tipo, strings.Join(vacios, ", "))
+	}
+	return nil
+}
+
+// costo devuelve el costo pedido o el por defecto
+func (d definicion) costo() float64 {
+	if d.Costo == nil {
+		return toolchain.COSTO_POR_DEFECTO
+	}
+	return *d.Costo
+}
+
+// programaJSON es un programa en las respuestas
+type programaJSON struct {
+	Nombre   string `json:"nombre"`
+	Lenguaje string `json:"lenguaje"`
+}
+
+// afectadoJSON es un programa que dejó de ser ejecutable, con las plataformas donde ya no se
+// puede ejecutar
+type afectadoJSON struct {
+	programaJSON
+	Plataformas []string `json:"plataformas"`
+}
+
+// definir agrega la definición al espacio, creándolo si no existía. Responde 201, 409 si
+// ya estaba definida o 400 si está mal escrita.
+func (s *servidor) definir(w http.ResponseWriter, r *http.Request) {
+	var d definicion
+	if err := leerCuerpo(w, r, &d); err != nil {
+		responderError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, ok := s.espacios[r.PathValue("espacio")]
+	if !ok {
+		tc = toolchain.New()
+		tc.UsarNormalizacion(s.normalizacion)
+	}
+
+	var err error
+	switch strings.ToLower(d.Tipo) {
+	case "programa":
+		if err = d.faltan(map[string]string{"nombre": d.Nombre, "lenguaje": d.Lenguaje}); err == nil {
+			err = tc.DefinirPrograma(d.Nombre, d.Lenguaje)
+		}
+	case "interprete":
+		if err = d.faltan(map[string]string{"lenguaje_base": d.LenguajeBase, "lenguaje": d.Lenguaje}); err == nil {
+			err = tc.DefinirInterpreteConCosto(d.LenguajeBase, d.Lenguaje, d.costo())
+		}
+	case "traductor":
+		if err = d.faltan(map[string]string{"lenguaje_base": d.LenguajeBase, "lenguaje_origen": d.LenguajeOrigen, "lenguaje_destino": d.LenguajeDestino}); err == nil {
+			err = tc.DefinirTraductorConCosto(d.LenguajeBase, d.LenguajeOrigen, d.LenguajeDestino, d.costo())
+		}
+	case "plataforma":
+		if err = d.faltan(map[string]string{"nombre": d.Nombre}); err == nil {
+			err = tc.DefinirPlataforma(d.Nombre)
+		}
+	default:
+		err = invalido("tipo de definición '%s' desconocido, usa programa, interprete, traductor o plataforma", d.Tipo)
+	}
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	s.espacios[r.PathValue("espacio")] = tc
+	responder(w, http.StatusCreated, d)
+}
+
+// eliminar borra la definición y responde con los programas que dejaron de ser ejecutables,
+// 404 si no estaba definida o 400 si está mal escrita
+func (s *servidor) eliminar(w http.ResponseWriter, r *http.Request) {
+	var d definicion
+	if err := leerCuerpo(w, r, &d); err != nil {
+		responderError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, err := s.espacio(r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+
+	var afectados []toolchain.Afectado
+	switch strings.ToLower(d.Tipo) {
+	case "programa":
+		if err = d.faltan(map[string]string{"nombre": d.Nombre}); err == nil {
+			err = tc.EliminarPrograma(d.Nombre)
+		}
+	case "interprete":
+		if err = d.faltan(map[string]string{"lenguaje_base": d.LenguajeBase, "lenguaje": d.Lenguaje}); err == nil {
+			afectados, err = tc.EliminarInterprete(d.LenguajeBase, d.Lenguaje)
+		}
+	case "traductor":
+		if err = d.faltan(map[string]string{"lenguaje_base": d.LenguajeBase, "lenguaje_origen": d.LenguajeOrigen, "lenguaje_destino": d.LenguajeDestino}); err == nil {
+			afectados, err = tc.EliminarTraductor(d.LenguajeBase, d.LenguajeOrigen, d.LenguajeDestino)
+		}
+	default:
+		err = invalido("tipo de definición '%s' desconocido, usa programa, interprete o traductor", d.Tipo)
+	}
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	respuesta := struct {
+		Afectados []afectadoJSON `json:"afectados"`
+	}{Afectados: []afectadoJSON{}}
+	for _, a := range afectados {
+		respuesta.Afectados = append(respuesta.Afectados, afectadoJSON{programaJSON{a.Nombre, a.Lenguaje}, a.Plataformas})
+	}
+	responder(w, http.StatusOK, respuesta)
+}
+
+func (s *servidor) listarProgramas(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, err := s.espacio(r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	programas := []programaJSON{}
+	for _, prog := range tc.Programas() {
+		programas = append(programas, programaJSON{prog.Nombre, prog.Lenguaje})
+	}
+	responder(w, http.StatusOK, map[string][]programaJSON{"programas": programas})
+}
+
+// listarInterpretes responde con cada intérprete escrito como el cuerpo que lo define, así
+// se puede mandar tal cual para eliminarlo
+func (s *servidor) listarInterpretes(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, err := s.espacio(r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	interpretes := []definicion{}
+	for _, interp := range tc.Interpretes() {
+		costo := interp.Costo
+		interpretes = append(interpretes, definicion{
+			Tipo: "interprete", LenguajeBase: interp.EspecificacionBase(), Lenguaje: interp.Lenguaje, Costo: &costo,
+		})
+	}
+	responder(w, http.StatusOK, map[string][]definicion{"interpretes": interpretes})
+}
+
+// listarTraductores responde con cada traductor escrito como el cuerpo que lo define
+func (s *servidor) listarTraductores(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, err := s.espacio(r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	traductores := []definicion{}
+	for _, trad := range tc.Traductores() {
+		costo := trad.Costo
+		traductores = append(traductores, definicion{
+			Tipo: "traductor", LenguajeBase: trad.LenguajeBase, LenguajeOrigen: trad.EspecificacionOrigen(),
+			LenguajeDestino: trad.LenguajeDestino, Costo: &costo,
+		})
+	}
+	responder(w, http.StatusOK, map[string][]definicion{"traductores": traductores})
+}
+
+// rutaJSON es la respuesta de la consulta de ruta; sin ruta, ejecutable es falso y el resto
+// del camino va vacío
+type rutaJSON struct {
+	Programa     string   `json:"programa"`
+	Lenguaje     string   `json:"lenguaje"`
+	Plataforma   string   `json:"plataforma"`
+	Criterio     string   `json:"criterio"`
+	Ejecutable   bool     `json:"ejecutable"`
+	Lenguajes    []string `json:"lenguajes"`
+	Herramientas []string `json:"herramientas"`
+	Costo        float64  `json:"costo"`
+}
+
+// ruta responde si el programa se puede ejecutar en la plataforma y por dónde, como EJECUTABLE
+func (s *servidor) ruta(w http.ResponseWriter, r *http.Request) {
+	criterio := strings.ToLower(r.URL.Query().Get("criterio"))
+	if criterio == "" {
+		criterio = "corta"
+	}
+	if criterio != "corta" && criterio != "barata" {
+		responderError(w, invalido("el criterio de ruta debe ser corta o barata"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, err := s.espacio(r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	prog, ok := tc.Programa(r.PathValue("programa"))
+	if !ok {
+		responderError(w, noEncontrado("el programa '%s' no está definido", r.PathValue("programa")))
+		return
+	}
+	plataforma, err := plataformaPedida(tc, r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+
+	var ruta toolchain.Ruta
+	if criterio == "barata" {
+		ruta, ok = tc.RutaMasBarataEn(prog.Lenguaje, plataforma)
+	} else {
+		ruta, ok = tc.RutaMasCortaEn(prog.Lenguaje, plataforma)
+	}
+	respuesta := rutaJSON{
+		Programa: prog.Nombre, Lenguaje: prog.Lenguaje, Plataforma: plataforma, Criterio: criterio,
+		Ejecutable: ok, Lenguajes: []string{}, Herramientas: []string{},
+	}
+	if ok {
+		respuesta.Lenguajes, respuesta.Herramientas, respuesta.Costo = ruta.Lenguajes, ruta.Herramientas, ruta.Costo
+	}
+	responder(w, http.StatusOK, respuesta)
+}
+
+// plataformaPedida lee el parámetro plataforma, que por defecto es LOCAL y tiene que estar definida
+func plataformaPedida(tc *toolchain.Toolchain, r *http.Request) (string, error) {
+	pedida := r.URL.Query().Get("plataforma")
+	if pedida == "" {
+		return toolchain.LENGUAJE_LOCAL, nil
+	}
+	if plataforma := tc.NombreLenguaje(pedida); slices.Contains(tc.Plataformas(), plataforma) {
+		return plataforma, nil
+	}
+	return "", noEncontrado("la plataforma '%s' no está definida", pedida)
+}
+
+// grafo responde con el catálogo exportado en DOT o Mermaid, resaltando la ruta más corta
+// del programa hasta la plataforma si se pide uno, como EXPORTAR
+func (s *servidor) grafo(w http.ResponseWriter, r *http.Request) {
+	formato := strings.ToLower(r.URL.Query().Get("formato"))
+	if formato == "" {
+		formato = "dot"
+	}
+	if formato != "dot" && formato != "mermaid" {
+		responderError(w, invalido("el formato debe ser dot o mermaid"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, err := s.espacio(r)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	var opciones toolchain.OpcionesExportar
+	if nombre := r.URL.Query().Get("programa"); nombre != "" {
+		prog, ok := tc.Programa(nombre)
+		if !ok {
+			responderError(w, noEncontrado("el programa '%s' no está definido", nombre))
+			return
+		}
+		plataforma, err := plataformaPedida(tc, r)
+		if err != nil {
+			responderError(w, err)
+			return
+		}
+		if ruta, ok := tc.RutaMasCortaEn(prog.Lenguaje, plataforma); ok {
+			opciones.Ruta = ruta.Lenguajes
+		}
+	}
+
+	var buf bytes.Buffer
+	if formato == "dot" {
+		err = tc.ExportarDOT(&buf, opciones)
+	} else {
+		err = tc.ExportarMermaid(&buf, opciones)
+	}
+	if err != nil {
+		responderError(w, errorHTTP{http.StatusInternalServerError, err.Error()})
+		return
+	}
+	responder(w, http.StatusOK, map[string]string{"formato": formato, "grafo": buf.String()})
+}
@@ -0,0 +1,293 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"pregunta5/toolchain"
+)
+
+// pedir hace una petición al servidor de prueba y devuelve el estado y el cuerpo
+func pedir(t *testing.T, srv *httptest.Server, metodo, ruta, cuerpo string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(metodo, srv.URL+ruta, strings.NewReader(cuerpo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	leido, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(leido)
+}
+
+// decodificar lee el JSON de una respuesta o falla la prueba
+func decodificar(t *testing.T, cuerpo string, destino any) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(cuerpo), destino); err != nil {
+		t.Fatalf("Respuesta que no es JSON: %v\n%s", err, cuerpo)
+	}
+}
+
+// Prueba las definiciones y sus errores: 201, 409 si se repite y 400 si está mal escrita
+func TestServidorDefinir(t *testing.T) {
+	srv := httptest.NewServer(nuevoServidor(toolchain.IgnorarMayusculas).rutas())
+	defer srv.Close()
+
+	casos := []struct {
+		cuerpo string
+		estado int
+		error  string
+	}{
+		{`{"tipo": "interprete", "lenguaje_base": "GO", "lenguaje": "LOCAL"}`, http.StatusCreated, ""},
+		{`{"tipo": "programa", "nombre": "app", "lenguaje": "GO"}`, http.StatusCreated, ""},
+		{`{"tipo": "traductor", "lenguaje_base": "GO", "lenguaje_origen": "TS", "lenguaje_destino": "GO", "costo": 3}`, http.StatusCreated, ""},
+		{`{"tipo": "plataforma", "nombre": "ARM"}`, http.StatusCreated, ""},
+		{`{"tipo": "INTERPRETE", "lenguaje_base": "go", "lenguaje": "local"}`, http.StatusConflict, "ya estaba definido"},
+		{`{"tipo": "compilador", "nombre": "x"}`, http.StatusBadRequest, "tipo de definición 'compilador' desconocido"},
+		{`{"tipo": "traductor", "lenguaje_base": "GO"}`, http.StatusBadRequest, "le falta lenguaje_origen, lenguaje_destino"},
+		{`{"tipo": "programa", "nombre": "x", "lenguage": "GO"}`, http.StatusBadRequest, "unknown field"},
+		{`{"tipo": "interprete", "lenguaje_base": "C", "lenguaje": "LOCAL", "costo": -1}`, http.StatusBadRequest, "negativo"},
+		{`no es json`, http.StatusBadRequest, "cuerpo inválido"},
+	}
+	for _, caso := range casos {
+		estado, cuerpo := pedir(t, srv, "POST", "/espacios/docs/definiciones", caso.cuerpo)
+		if estado != caso.estado || !strings.Contains(cuerpo, caso.error) {
+			t.Errorf("POST %s = %d %s; se esperaba %d con %q", caso.cuerpo, estado, cuerpo, caso.estado, caso.error)
+		}
+	}
+
+	// El catálogo del espacio se lee en el formato de GUARDAR
+	estado, cuerpo := pedir(t, srv, "GET", "/espacios/docs/catalogo", "")
+	if estado != http.StatusOK {
+		t.Fatalf("GET catalogo = %d %s", estado, cuerpo)
+	}
+	tc, err := toolchain.Cargar(strings.NewReader(cuerpo))
+	if err != nil {
+		t.Fatalf("El catálogo no se pudo cargar: %v", err)
+	}
+	if _, ok := tc.Programa("app"); !ok || len(tc.Interpretes()) != 1 || len(tc.Traductores()) != 1 || len(tc.Plataformas()) != 2 {
+		t.Errorf("Catálogo incompleto:\n%s", cuerpo)
+	}
+}
+
+// Prueba los listados de programas y herramientas y que un elemento listado sirve para eliminarlo
+func TestServidorListas(t *testing.T) {
+	srv := httptest.NewServer(nuevoServidor(toolchain.IgnorarMayusculas).rutas())
+	defer srv.Close()
+	for _, d := range []string{
+		`{"tipo": "programa", "nombre": "web", "lenguaje": "TS"}`,
+		`{"tipo": "programa", "nombre": "app", "lenguaje": "GO"}`,
+		`{"tipo": "interprete", "lenguaje_base": "Python>=3.8", "lenguaje": "LOCAL", "costo": 2}`,
+		`{"tipo": "traductor", "lenguaje_base": "GO", "lenguaje_origen": "TS", "lenguaje_destino": "GO"}`,
+	} {
+		pedir(t, srv, "POST", "/espacios/docs/definiciones", d)
+	}
+
+	casos := []struct {
+		ruta   string
+		cuerpo string
+	}{
+		{"/espacios/docs/programas", `{"programas":[{"nombre":"app","lenguaje":"GO"},{"nombre":"web","lenguaje":"TS"}]}`},
+		{"/espacios/docs/interpretes", `{"interpretes":[{"tipo":"interprete","lenguaje":"LOCAL","lenguaje_base":"Python>=3.8","costo":2}]}`},
+		{"/espacios/docs/traductores", `{"traductores":[{"tipo":"traductor","lenguaje_base":"GO","lenguaje_origen":"TS","lenguaje_destino":"GO","costo":1}]}`},
+	}
+	for _, caso := range casos {
+		estado, cuerpo := pedir(t, srv, "GET", caso.ruta, "")
+		if estado != http.StatusOK || cuerpo != caso.cuerpo+"\n" {
+			t.Errorf("GET %s = %d %s; se esperaba %s", caso.ruta, estado, cuerpo, caso.cuerpo)
+		}
+	}
+
+	var lista struct{ Interpretes []json.RawMessage }
+	_, cuerpo := pedir(t, srv, "GET", "/espacios/docs/interpretes", "")
+	decodificar(t, cuerpo, &lista)
+	if estado, cuerpo := pedir(t, srv, "DELETE", "/espacios/docs/definiciones", string(lista.Interpretes[0])); estado != http.StatusOK {
+		t.Errorf("No se pudo eliminar un intérprete listado: %d %s", estado, cuerpo)
+	}
+	if estado, _ := pedir(t, srv, "GET", "/espacios/nada/programas", ""); estado != http.StatusNotFound {
+		t.Errorf("Listar en un espacio que no existe debería dar 404, dio %d", estado)
+	}
+}
+
+// Prueba la consulta de ruta con los criterios, las plataformas y los errores
+func TestServidorRuta(t *testing.T) {
+	srv := httptest.NewServer(nuevoServidor(toolchain.IgnorarMayusculas).rutas())
+	defer srv.Close()
+	for _, d := range []string{
+		`{"tipo": "interprete", "lenguaje_base": "C", "lenguaje": "LOCAL"}`,
+		`{"tipo": "interprete", "lenguaje_base": "Java", "lenguaje": "C", "costo": 5}`,
+		`{"tipo": "traductor", "lenguaje_base": "C", "lenguaje_origen": "Java", "lenguaje_destino": "C"}`,
+		`{"tipo": "programa", "nombre": "mi app", "lenguaje": "Java"}`,
+		`{"tipo": "programa", "nombre": "web", "lenguaje": "TS"}`,
+	} {
+		if estado, cuerpo := pedir(t, srv, "POST", "/espacios/docs/definiciones", d); estado != http.StatusCreated {
+			t.Fatalf("POST %s = %d %s", d, estado, cuerpo)
+		}
+	}
+
+	var ruta rutaJSON
+	estado, cuerpo := pedir(t, srv, "GET", "/espacios/docs/programas/mi%20app/ruta", "")
+	decodificar(t, cuerpo, &ruta)
+	if estado != http.StatusOK || !ruta.Ejecutable || !slices.Equal(ruta.Lenguajes, []string{"Java", "C", "LOCAL"}) || ruta.Costo != 6 || ruta.Criterio != "corta" {
+		t.Errorf("Ruta corta incorrecta: %d %s", estado, cuerpo)
+	}
+	_, cuerpo = pedir(t, srv, "GET", "/espacios/docs/programas/mi%20app/ruta?criterio=BARATA", "")
+	decodificar(t, cuerpo, &ruta)
+	if !ruta.Ejecutable || ruta.Costo != 2 || !slices.Equal(ruta.Herramientas, []string{"traductor de C de Java a C", "intérprete de C en LOCAL"}) {
+		t.Errorf("Ruta barata incorrecta: %s", cuerpo)
+	}
+	_, cuerpo = pedir(t, srv, "GET", "/espacios/docs/programas/web/ruta", "")
+	decodificar(t, cuerpo, &ruta)
+	if ruta.Ejecutable || ruta.Lenguajes == nil || len(ruta.Lenguajes) != 0 {
+		t.Errorf("El programa web no debería ser ejecutable: %s", cuerpo)
+	}
+
+	errores := []struct {
+		ruta   string
+		estado int
+	}{
+		{"/espacios/docs/programas/nada/ruta", http.StatusNotFound},
+		{"/espacios/otro/programas/web/ruta", http.StatusNotFound},
+		{"/espacios/docs/programas/web/ruta?criterio=rapida", http.StatusBadRequest},
+		{"/espacios/docs/programas/web/ruta?plataforma=ARM", http.StatusNotFound},
+	}
+	for _, caso := range errores {
+		if estado, cuerpo := pedir(t, srv, "GET", caso.ruta, ""); estado != caso.estado || !strings.Contains(cuerpo, `"error"`) {
+			t.Errorf("GET %s = %d %s; se esperaba %d", caso.ruta, estado, cuerpo, caso.estado)
+		}
+	}
+}
+
+// Prueba que eliminar responde con los programas afectados, 404 si no estaba y 400 si está mal
+// escrito, y que los espacios son independientes
+func TestServidorEliminarYEspacios(t *testing.T) {
+	srv := httptest.NewServer(nuevoServidor(toolchain.IgnorarMayusculas).rutas())
+	defer srv.Close()
+	for _, espacio := range []string{"uno", "dos"} {
+		for _, d := range []string{
+			`{"tipo": "interprete", "lenguaje_base": "GO", "lenguaje": "LOCAL"}`,
+			`{"tipo": "programa", "nombre": "app", "lenguaje": "GO"}`,
+		} {
+			pedir(t, srv, "POST", "/espacios/"+espacio+"/definiciones", d)
+		}
+	}
+
+	interprete := `{"tipo": "interprete", "lenguaje_base": "GO", "lenguaje": "LOCAL"}`
+	estado, cuerpo := pedir(t, srv, "DELETE", "/espacios/uno/definiciones", interprete)
+	if estado != http.StatusOK || cuerpo != `{"afectados":[{"nombre":"app","lenguaje":"GO","plataformas":["LOCAL"]}]}`+"\n" {
+		t.Errorf("DELETE interprete = %d %s", estado, cuerpo)
+	}
+	if estado, _ := pedir(t, srv, "DELETE", "/espacios/uno/definiciones", interprete); estado != http.StatusNotFound {
+		t.Errorf("Eliminar algo que no está debería dar 404, dio %d", estado)
+	}
+	malEscrito := `{"tipo": "interprete", "lenguaje_base": "Python>=x", "lenguaje": "LOCAL"}`
+	if estado, cuerpo := pedir(t, srv, "DELETE", "/espacios/uno/definiciones", malEscrito); estado != http.StatusBadRequest {
+		t.Errorf("Eliminar con una versión mal escrita debería dar 400: %d %s", estado, cuerpo)
+	}
+	if estado, cuerpo := pedir(t, srv, "DELETE", "/espacios/uno/definiciones", `{"tipo": "programa", "nombre": "otra"}`); estado != http.StatusNotFound {
+		t.Errorf("Eliminar un programa que no está debería dar 404: %d %s", estado, cuerpo)
+	}
+	if estado, cuerpo := pedir(t, srv, "DELETE", "/espacios/uno/definiciones", `{"tipo": "plataforma", "nombre": "LOCAL"}`); estado != http.StatusBadRequest {
+		t.Errorf("Las plataformas no se eliminan: %d %s", estado, cuerpo)
+	}
+
+	// El otro espacio no cambió
+	var ruta rutaJSON
+	_, cuerpo = pedir(t, srv, "GET", "/espacios/dos/programas/app/ruta", "")
+	decodificar(t, cuerpo, &ruta)
+	if !ruta.Ejecutable {
+		t.Errorf("Eliminar en un espacio no debería afectar a otro: %s", cuerpo)
+	}
+
+	var lista struct{ Espacios []string }
+	_, cuerpo = pedir(t, srv, "GET", "/espacios", "")
+	decodificar(t, cuerpo, &lista)
+	if !slices.Equal(lista.Espacios, []string{"dos", "uno"}) {
+		t.Errorf("Espacios incorrectos: %s", cuerpo)
+	}
+	if estado, _ := pedir(t, srv, "DELETE", "/espacios/uno", ""); estado != http.StatusNoContent {
+		t.Errorf("Borrar un espacio debería dar 204, dio %d", estado)
+	}
+	if estado, _ := pedir(t, srv, "GET", "/espacios/uno/catalogo", ""); estado != http.StatusNotFound {
+		t.Errorf("Un espacio borrado no debería existir, dio %d", estado)
+	}
+}
+
+// Prueba la exportación del grafo en los dos formatos con la ruta resaltada
+func TestServidorGrafo(t *testing.T) {
+	servidor := nuevoServidor(toolchain.IgnorarMayusculas)
+	tc := toolchain.New()
+	tc.DefinirInterprete("GO", "LOCAL")
+	tc.DefinirPrograma("app", "GO")
+	servidor.agregarEspacio(ESPACIO_PRINCIPAL, tc)
+	srv := httptest.NewServer(servidor.rutas())
+	defer srv.Close()
+
+	var grafo struct{ Formato, Grafo string }
+	estado, cuerpo := pedir(t, srv, "GET", "/espacios/principal/grafo?programa=app", "")
+	decodificar(t, cuerpo, &grafo)
+	var esperado strings.Builder
+	tc.ExportarDOT(&esperado, toolchain.OpcionesExportar{Ruta: []string{"GO", "LOCAL"}})
+	if estado != http.StatusOK || grafo.Formato != "dot" || grafo.Grafo != esperado.String() {
+		t.Errorf("Grafo DOT incorrecto: %d %s", estado, cuerpo)
+	}
+	_, cuerpo = pedir(t, srv, "GET", "/espacios/principal/grafo?formato=mermaid", "")
+	decodificar(t, cuerpo, &grafo)
+	if !strings.HasPrefix(grafo.Grafo, "graph LR") {
+		t.Errorf("Grafo Mermaid incorrecto: %s", cuerpo)
+	}
+	if estado, _ := pedir(t, srv, "GET", "/espacios/principal/grafo?formato=svg", ""); estado != http.StatusBadRequest {
+		t.Errorf("Un formato desconocido debería dar 400, dio %d", estado)
+	}
+	if estado, _ := pedir(t, srv, "GET", "/espacios/principal/grafo?programa=nada", ""); estado != http.StatusNotFound {
+		t.Errorf("Un programa desconocido debería dar 404, dio %d", estado)
+	}
+
+	// En otra plataforma el programa no tiene ruta y no se resalta nada
+	tc.DefinirPlataforma("ARM")
+	_, cuerpo = pedir(t, srv, "GET", "/espacios/principal/grafo?programa=app&plataforma=arm", "")
+	decodificar(t, cuerpo, &grafo)
+	esperado.Reset()
+	tc.ExportarDOT(&esperado, toolchain.OpcionesExportar{})
+	if grafo.Grafo != esperado.String() {
+		t.Errorf("Grafo con ruta a ARM incorrecto: %s", cuerpo)
+	}
+	if estado, _ := pedir(t, srv, "GET", "/espacios/principal/grafo?programa=app&plataforma=X86", ""); estado != http.StatusNotFound {
+		t.Errorf("Una plataforma desconocida debería dar 404, dio %d", estado)
+	}
+}
+
+// Prueba que las peticiones simultáneas no pierden definiciones
+func TestServidorConcurrente(t *testing.T) {
+	srv := httptest.NewServer(nuevoServidor(toolchain.IgnorarMayusculas).rutas())
+	defer srv.Close()
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pedir(t, srv, "POST", "/espacios/docs/definiciones", fmt.Sprintf(`{"tipo": "programa", "nombre": "p%d", "lenguaje": "GO"}`, i))
+			pedir(t, srv, "GET", fmt.Sprintf("/espacios/docs/programas/p%d/ruta", i), "")
+		}()
+	}
+	wg.Wait()
+	_, cuerpo := pedir(t, srv, "GET", "/espacios/docs/catalogo", "")
+	tc, err := toolchain.Cargar(strings.NewReader(cuerpo))
+	if err != nil || len(tc.Programas()) != 20 {
+		t.Errorf("Se esperaban 20 programas: %v\n%s", err, cuerpo)
+	}
+}
@@ -0,0 +1,214 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"slices"
+	"sort"
+)
+
+// Paso es un lenguaje que se vuelve ejecutable en una etapa del arranque y la herramienta
+// que lo permite; en la etapa 0 la herramienta es el intérprete semilla
+type Paso struct {
+	Lenguaje    string
+	Herramienta string
+}
+
+// Ciclo es un grupo de lenguajes que dependen unos de otros a través de sus herramientas
+// (una componente fuertemente conexa del grafo de dependencias)
+type Ciclo struct {
+	Lenguajes []string
+	Resuelto  bool     // Todos sus lenguajes ya son ejecutables en la plataforma
+	Semillas  []string // Lenguajes a los que basta darles un intérprete en la plataforma para resolverlo
+	Etapas    [][]Paso // Orden de construcción a partir de las semillas
+}
+
+// Bootstrapping reúne las herramientas autoalojadas y los ciclos del catálogo, vistos desde
+// una plataforma
+type Bootstrapping struct {
+	Plataforma   string
+	Autoalojadas []string // Herramientas escritas en el mismo lenguaje que procesan
+	Ciclos       []Ciclo
+}
+
+// AnalizarBootstrapping busca herramientas autoalojadas y ciclos de dependencias. Un lenguaje
+// L depende de M si un intérprete de L está escrito en M, o si un traductor desde L está
+// escrito en M o traduce a M. Para cada ciclo sin resolver elige semillas de forma golosa
+// (cada vez la que más lenguajes del ciclo resuelve, con empates por nombre) y arma las etapas.
+// Si la plataforma no está definida no se eligen semillas.
+func (tc *Toolchain) AnalizarBootstrapping(plataforma string) Bootstrapping {
+	plataforma = tc.NombreLenguaje(plataforma)
+	analisis := Bootstrapping{Plataforma: plataforma}
+	dependencias := make(map[string][]string)
+	for _, interp := range tc.interpretes {
+		if interp.acepta(interp.Lenguaje) {
+			analisis.Autoalojadas = append(analisis.Autoalojadas, interp.String())
+		}
+		for _, nodo := range tc.nodosCompatibles(interp.LenguajeBase, interp.Versiones) {
+			dependencias[nodo] = append(dependencias[nodo], interp.Lenguaje)
+		}
+	}
+	for _, trad := range tc.traductores {
+		if trad.acepta(trad.LenguajeBase) {
+			analisis.Autoalojadas = append(analisis.Autoalojadas, trad.String())
+		}
+		for _, nodo := range tc.nodosCompatibles(trad.LenguajeOrigen, trad.VersionesOrigen) {
+			dependencias[nodo] = append(dependencias[nodo], trad.LenguajeBase, trad.LenguajeDestino)
+		}
+	}
+
+	for _, componente := range componentesFuertes(tc.nodos(), dependencias) {
+		if len(componente) == 1 && !slices.Contains(dependencias[componente[0]], componente[0]) {
+			continue // Un lenguaje suelto solo es un ciclo si depende de sí mismo
+		}
+		ciclo := Ciclo{Lenguajes: componente, Resuelto: true}
+		for _, l := range componente {
+			if !tc.estado.es(plataforma, l) {
+				ciclo.Resuelto = false
+			}
+		}
+		if !ciclo.Resuelto && slices.Contains(tc.plataformas, plataforma) {
+			ciclo.Semillas = tc.semillasPara(componente, plataforma)
+			ciclo.Etapas = tc.etapasDeArranque(ciclo.Semillas, plataforma)
+		}
+		analisis.Ciclos = append(analisis.Ciclos, ciclo)
+	}
+	return analisis
+}
+
+// semillasPara elige de forma golosa intérpretes en la plataforma hasta que todo el ciclo sea
+// ejecutable en ella
+func (tc *Toolchain) semillasPara(componente []string, plataforma string) []string {
+	e := tc.estado.clonar()
+	var semillas []string
+	for {
+		pendientes := 0
+		for _, l := range componente {
+			if !e.es(plataforma, l) {
+				pendientes++
+			}
+		}
+		if pendientes == 0 {
+			return semillas
+		}
+
+		mejor, mejorResueltos := "", -1
+		for _, candidato := range componente {
+			if e.es(plataforma, candidato) {
+				continue
+			}
+			prueba := e.clonar()
+			tc.marcar(prueba, plataforma, candidato)
+			resueltos := 0
+			for _, l := range componente {
+				if prueba.es(plataforma, l) {
+					resueltos++
+				}
+			}
+			if resueltos > mejorResueltos {
+				mejor, mejorResueltos = candidato, resueltos
+			}
+		}
+		semillas = append(semillas, mejor)
+		tc.marcar(e, plataforma, mejor)
+	}
+}
+
+// etapasDeArranque simula la construcción por rondas: en cada etapa se agregan los lenguajes
+// que se pueden ejecutar con herramientas cuyos requisitos se cumplieron en etapas anteriores
+func (tc *Toolchain) etapasDeArranque(semillas []string, plataforma string) [][]Paso {
+	e := tc.estado.clonar()
+	var inicial []Paso
+	for _, semilla := range semillas {
+		e.en[plataforma][semilla] = true
+		e.enAlguna[semilla] = true
+		inicial = append(inicial, Paso{Lenguaje: semilla, Herramienta: interpreteDe(semilla, plataforma).String() + " (semilla)"})
+	}
+	etapas := [][]Paso{inicial}
+
+	for {
+		var etapa []Paso
+		nuevos := make(map[string]bool)
+		for _, interp := range tc.interpretes {
+			if !interpreteUsable(e, plataforma, interp) {
+				continue
+			}
+			for _, nodo := range tc.nodosCompatibles(interp.LenguajeBase, interp.Versiones) {
+				if !e.es(plataforma, nodo) && !nuevos[nodo] {
+					nuevos[nodo] = true
+					etapa = append(etapa, Paso{Lenguaje: nodo, Herramienta: interp.String()})
+				}
+			}
+		}
+		for _, trad := range tc.traductores {
+			if !traductorUsable(e, plataforma, trad) {
+				continue
+			}
+			for _, nodo := range tc.nodosCompatibles(trad.LenguajeOrigen, trad.VersionesOrigen) {
+				if !e.es(plataforma, nodo) && !nuevos[nodo] {
+					nuevos[nodo] = true
+					etapa = append(etapa, Paso{Lenguaje: nodo, Herramienta: trad.String()})
+				}
+			}
+		}
+		if len(etapa) == 0 {
+			return etapas
+		}
+		sort.Slice(etapa, func(i, j int) bool { return etapa[i].Lenguaje < etapa[j].Lenguaje })
+		for l := range nuevos {
+			e.en[plataforma][l] = true
+			e.enAlguna[l] = true
+		}
+		etapas = append(etapas, etapa)
+	}
+}
+
+// componentesFuertes calcula con el algoritmo de Tarjan las componentes fuertemente conexas.
+// Cada componente sale ordenada y las componentes se ordenan por su primer lenguaje.
+func componentesFuertes(nodos []string, aristas map[string][]string) [][]string {
+	indice := make(map[string]int)
+	bajo := make(map[string]int)
+	enPila := make(map[string]bool)
+	var pila []string
+	var componentes [][]string
+	contador := 0
+
+	var visitar func(v string)
+	visitar = func(v string) {
+		indice[v] = contador
+		bajo[v] = contador
+		contador++
+		pila = append(pila, v)
+		enPila[v] = true
+
+		for _, w := range aristas[v] {
+			if _, visto := indice[w]; !visto {
+				visitar(w)
+				bajo[v] = min(bajo[v], bajo[w])
+			} else if enPila[w] {
+				bajo[v] = min(bajo[v], indice[w])
+			}
+		}
+
+		if bajo[v] == indice[v] {
+			var componente []string
+			for {
+				w := pila[len(pila)-1]
+				pila = pila[:len(pila)-1]
+				enPila[w] = false
+				componente = append(componente, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Strings(componente)
+			componentes = append(componentes, componente)
+		}
+	}
+	for _, v := range nodos {
+		if _, visto := indice[v]; !visto {
+			visitar(v)
+		}
+	}
+	sort.Slice(componentes, func(i, j int) bool { return componentes[i][0] < componentes[j][0] })
+	return componentes
+}
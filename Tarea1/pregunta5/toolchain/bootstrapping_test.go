@@ -0,0 +1,84 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"strings"
+	"testing"
+)
+
+// Prueba el caso clásico: un compilador de X a LOCAL escrito en X
+func TestBootstrappingAutoalojado(t *testing.T) {
+	tc := New()
+	tc.DefinirTraductor("X", "X", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("X", "Y", LENGUAJE_LOCAL)
+
+	analisis := tc.AnalizarBootstrapping(LENGUAJE_LOCAL)
+	if len(analisis.Autoalojadas) != 1 || analisis.Autoalojadas[0] != "traductor de X de X a LOCAL" {
+		t.Errorf("Autoalojadas incorrectas: %v", analisis.Autoalojadas)
+	}
+	if len(analisis.Ciclos) != 1 {
+		t.Fatalf("Se esperaba un ciclo: %+v", analisis.Ciclos)
+	}
+	ciclo := analisis.Ciclos[0]
+	if strings.Join(ciclo.Lenguajes, ",") != "X" || ciclo.Resuelto || strings.Join(ciclo.Semillas, ",") != "X" {
+		t.Errorf("Ciclo incorrecto: %+v", ciclo)
+	}
+	if len(ciclo.Etapas) != 2 || ciclo.Etapas[0][0].Lenguaje != "X" ||
+		ciclo.Etapas[1][0] != (Paso{Lenguaje: "Y", Herramienta: "traductor de X de Y a LOCAL"}) {
+		t.Errorf("Etapas incorrectas: %+v", ciclo.Etapas)
+	}
+
+	// Con un intérprete de X el ciclo queda resuelto
+	tc.DefinirInterprete("X", LENGUAJE_LOCAL)
+	if ciclo := tc.AnalizarBootstrapping(LENGUAJE_LOCAL).Ciclos[0]; !ciclo.Resuelto || ciclo.Semillas != nil {
+		t.Errorf("El ciclo debería estar resuelto: %+v", ciclo)
+	}
+}
+
+// Prueba un ciclo de tres lenguajes donde una sola semilla bien elegida alcanza
+func TestBootstrappingCicloLargo(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("A", "B")
+	tc.DefinirInterprete("B", "C")
+	tc.DefinirTraductor("A", "C", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("D", LENGUAJE_LOCAL)
+
+	analisis := tc.AnalizarBootstrapping(LENGUAJE_LOCAL)
+	if len(analisis.Autoalojadas) != 0 || len(analisis.Ciclos) != 1 {
+		t.Fatalf("Análisis incorrecto: %+v", analisis)
+	}
+	ciclo := analisis.Ciclos[0]
+	if strings.Join(ciclo.Lenguajes, ",") != "A,B,C" || strings.Join(ciclo.Semillas, ",") != "A" {
+		t.Errorf("Ciclo incorrecto: %+v", ciclo)
+	}
+	// Con A arrancan C (traductor escrito en A), luego B y luego nada más porque A ya estaba
+	var orden []string
+	for _, etapa := range ciclo.Etapas {
+		for _, paso := range etapa {
+			orden = append(orden, paso.Lenguaje)
+		}
+	}
+	if strings.Join(orden, ",") != "A,C,B" {
+		t.Errorf("Orden de construcción incorrecto: %v", orden)
+	}
+}
+
+// Prueba que las semillas se piden en la plataforma analizada
+func TestBootstrappingEnPlataforma(t *testing.T) {
+	tc := New()
+	tc.DefinirPlataforma("ARM")
+	tc.DefinirTraductor("X", "X", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("X", LENGUAJE_LOCAL)
+
+	if ciclo := tc.AnalizarBootstrapping(LENGUAJE_LOCAL).Ciclos[0]; !ciclo.Resuelto {
+		t.Errorf("El ciclo debería estar resuelto en LOCAL: %+v", ciclo)
+	}
+	analisis := tc.AnalizarBootstrapping("ARM")
+	ciclo := analisis.Ciclos[0]
+	if analisis.Plataforma != "ARM" || ciclo.Resuelto || strings.Join(ciclo.Semillas, ",") != "X" {
+		t.Fatalf("En ARM hace falta sembrar X: %+v", analisis)
+	}
+	if ciclo.Etapas[0][0].Herramienta != "intérprete de X en ARM (semilla)" {
+		t.Errorf("La semilla debería ser un intérprete en ARM: %+v", ciclo.Etapas)
+	}
+}
@@ -0,0 +1,149 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// String describe el intérprete como "intérprete de L en M"
+func (interp Interprete) String() string {
+	return fmt.Sprintf("intérprete de %s en %s", interp.EspecificacionBase(), interp.Lenguaje)
+}
+
+// String describe el traductor como "traductor de B de L a M"
+func (trad Traductor) String() string {
+	return fmt.Sprintf("traductor de %s de %s a %s", trad.LenguajeBase, trad.EspecificacionOrigen(), trad.LenguajeDestino)
+}
+
+// Lenguajes devuelve, ordenados, todos los lenguajes concretos que aparecen en el catálogo,
+// incluidas las plataformas. Lo que acepta una herramienta solo cuenta si no tiene rango.
+func (tc *Toolchain) Lenguajes() []string {
+	vistos := make(map[string]bool)
+	for _, plataforma := range tc.plataformas {
+		vistos[plataforma] = true
+	}
+	for _, prog := range tc.programas {
+		vistos[prog.Lenguaje] = true
+	}
+	for _, interp := range tc.interpretes {
+		if interp.Versiones == (Rango{}) {
+			vistos[interp.LenguajeBase] = true
+		}
+		vistos[interp.Lenguaje] = true
+	}
+	for _, trad := range tc.traductores {
+		if trad.VersionesOrigen == (Rango{}) {
+			vistos[trad.LenguajeOrigen] = true
+		}
+		vistos[trad.LenguajeBase] = true
+		vistos[trad.LenguajeDestino] = true
+	}
+	lenguajes := make([]string, 0, len(vistos))
+	for l := range vistos {
+		lenguajes = append(lenguajes, l)
+	}
+	sort.Strings(lenguajes)
+	return lenguajes
+}
+
+// ProgramasEjecutables devuelve, ordenados por nombre, los programas que se pueden ejecutar en
+// la plataforma
+func (tc *Toolchain) ProgramasEjecutables(plataforma string) []Programa {
+	var lista []Programa
+	for _, prog := range tc.Programas() {
+		if tc.EsEjecutableEn(prog.Lenguaje, plataforma) {
+			lista = append(lista, prog)
+		}
+	}
+	return lista
+}
+
+// VersionesEjecutables da, de menor a mayor, las versiones concretas conocidas de un lenguaje
+// sin versión que se ejecutan en la plataforma. Sirve para avisar que un lenguaje sin versión
+// no es ejecutable pero algunas de sus versiones sí, porque un rango no acepta la que falta.
+func (tc *Toolchain) VersionesEjecutables(lenguaje, plataforma string) []string {
+	lenguaje, plataforma = tc.NombreLenguaje(lenguaje), tc.NombreLenguaje(plataforma)
+	if _, version := dividirNodo(lenguaje); version != "" {
+		return nil
+	}
+	var versiones []string
+	for _, nodo := range tc.nodosDe[lenguaje] {
+		if _, version := dividirNodo(nodo); version != "" && tc.ejecutableEn(tc.estado, plataforma, nodo) {
+			versiones = append(versiones, nodo)
+		}
+	}
+	slices.SortFunc(versiones, func(a, b string) int {
+		_, va := dividirNodo(a)
+		_, vb := dividirNodo(b)
+		return compararVersiones(va, vb)
+	})
+	return versiones
+}
+
+// HerramientaUsable indica si una herramienta del catálogo se puede usar ahora mismo
+type HerramientaUsable[T any] struct {
+	Herramienta T
+	Usable      bool
+}
+
+// Descripcion reúne todo lo que el catálogo sabe de un lenguaje, visto desde una plataforma
+type Descripcion struct {
+	Lenguaje         string
+	Plataforma       string
+	Ejecutable       bool
+	Versiones        []string // Si no es ejecutable y no tiene versión, sus versiones que sí lo son
+	Programas        []Programa
+	InterpretesDe    []HerramientaUsable[Interprete] // Intérpretes que ejecutan este lenguaje
+	InterpretesEn    []HerramientaUsable[Interprete] // Intérpretes escritos en este lenguaje
+	TraductoresDesde []HerramientaUsable[Traductor]  // Traductores que parten de este lenguaje
+	TraductoresHacia []HerramientaUsable[Traductor]  // Traductores que llegan a este lenguaje
+	TraductoresEn    []HerramientaUsable[Traductor]  // Traductores escritos en este lenguaje
+}
+
+// Describir devuelve las herramientas que entran y salen del lenguaje, en orden de definición.
+// Para un lenguaje sin versión se muestran las herramientas que aceptan cualquiera de sus
+// versiones; con versión, solo las que la aceptan. Una herramienta es usable si sirve para
+// llegar a la plataforma.
+func (tc *Toolchain) Describir(lenguaje, plataforma string) Descripcion {
+	lenguaje, plataforma = tc.NombreLenguaje(lenguaje), tc.NombreLenguaje(plataforma)
+	desc := Descripcion{Lenguaje: lenguaje, Plataforma: plataforma, Ejecutable: tc.ejecutableEn(tc.estado, plataforma, lenguaje)}
+	if !desc.Ejecutable {
+		desc.Versiones = tc.VersionesEjecutables(lenguaje, plataforma)
+	}
+	usableInterp := func(interp Interprete) HerramientaUsable[Interprete] {
+		return HerramientaUsable[Interprete]{interp, interpreteUsable(tc.estado, plataforma, interp)}
+	}
+	usableTrad := func(trad Traductor) HerramientaUsable[Traductor] {
+		return HerramientaUsable[Traductor]{trad, traductorUsable(tc.estado, plataforma, trad)}
+	}
+	for _, prog := range tc.Programas() {
+		if prog.Lenguaje == lenguaje {
+			desc.Programas = append(desc.Programas, prog)
+		}
+	}
+	interpretes, traductores := tc.interpretesDe[lenguaje], tc.traductoresDesde[lenguaje]
+	if _, version := dividirNodo(lenguaje); version != "" {
+		interpretes, traductores = tc.interpretesPara(lenguaje), tc.traductoresPara(lenguaje)
+	}
+	for _, interp := range interpretes {
+		desc.InterpretesDe = append(desc.InterpretesDe, usableInterp(interp))
+	}
+	for _, interp := range tc.interpretesEn[lenguaje] {
+		desc.InterpretesEn = append(desc.InterpretesEn, usableInterp(interp))
+	}
+	for _, trad := range traductores {
+		desc.TraductoresDesde = append(desc.TraductoresDesde, usableTrad(trad))
+	}
+	for _, trad := range tc.traductoresSegunL[lenguaje] {
+		h := usableTrad(trad)
+		if trad.LenguajeDestino == lenguaje {
+			desc.TraductoresHacia = append(desc.TraductoresHacia, h)
+		}
+		if trad.LenguajeBase == lenguaje {
+			desc.TraductoresEn = append(desc.TraductoresEn, h)
+		}
+	}
+	return desc
+}
@@ -0,0 +1,74 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"strings"
+	"testing"
+)
+
+// Prueba la lista de lenguajes y de programas ejecutables
+func TestLenguajesYProgramasEjecutables(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("web", "TS")
+	tc.DefinirPrograma("script", "JS")
+	tc.DefinirPrograma("viejo", "COBOL")
+	tc.DefinirInterprete("JS", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("RUST", "TS", "JS")
+
+	if got := strings.Join(tc.Lenguajes(), ","); got != "COBOL,JS,LOCAL,RUST,TS" {
+		t.Errorf("Lenguajes incorrectos: %s", got)
+	}
+	ejecutables := tc.ProgramasEjecutables(LENGUAJE_LOCAL)
+	if len(ejecutables) != 1 || ejecutables[0].Nombre != "script" {
+		t.Errorf("Programas ejecutables incorrectos: %v", ejecutables)
+	}
+}
+
+// Prueba que Describir separa las herramientas según cómo tocan al lenguaje
+func TestDescribir(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("script", "JS")
+	tc.DefinirInterprete("JS", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("LUA", "JS")
+	tc.DefinirTraductor("RUST", "TS", "JS")
+	tc.DefinirTraductor("JS", "JS", "WASM")
+
+	desc := tc.Describir("JS", LENGUAJE_LOCAL)
+	if !desc.Ejecutable || len(desc.Programas) != 1 {
+		t.Errorf("Descripción básica incorrecta: %+v", desc)
+	}
+	if len(desc.InterpretesDe) != 1 || desc.InterpretesDe[0].Herramienta.String() != "intérprete de JS en LOCAL" || !desc.InterpretesDe[0].Usable {
+		t.Errorf("Intérpretes de JS incorrectos: %+v", desc.InterpretesDe)
+	}
+	if len(desc.InterpretesEn) != 1 || desc.InterpretesEn[0].Herramienta.LenguajeBase != "LUA" {
+		t.Errorf("Intérpretes escritos en JS incorrectos: %+v", desc.InterpretesEn)
+	}
+	if len(desc.TraductoresHacia) != 1 || desc.TraductoresHacia[0].Herramienta.String() != "traductor de RUST de TS a JS" || desc.TraductoresHacia[0].Usable {
+		t.Errorf("Traductores hacia JS incorrectos: %+v", desc.TraductoresHacia)
+	}
+	// El traductor a WASM está escrito en JS pero no sirve porque WASM no es ejecutable
+	if len(desc.TraductoresDesde) != 1 || len(desc.TraductoresEn) != 1 || desc.TraductoresEn[0].Usable {
+		t.Errorf("Traductores desde o en JS incorrectos: %+v %+v", desc.TraductoresDesde, desc.TraductoresEn)
+	}
+}
+
+// Prueba que las consultas miran la plataforma pedida
+func TestConsultasEnPlataforma(t *testing.T) {
+	tc := New()
+	tc.DefinirPlataforma("X86")
+	tc.DefinirPrograma("app", "C")
+	tc.DefinirInterprete("C", "X86")
+
+	if len(tc.ProgramasEjecutables(LENGUAJE_LOCAL)) != 0 {
+		t.Errorf("Ningún programa debería ser ejecutable en LOCAL")
+	}
+	if ejecutables := tc.ProgramasEjecutables("X86"); len(ejecutables) != 1 || ejecutables[0].Nombre != "app" {
+		t.Errorf("Programas ejecutables en X86 incorrectos: %v", ejecutables)
+	}
+	if desc := tc.Describir("C", LENGUAJE_LOCAL); desc.Ejecutable || desc.InterpretesDe[0].Usable {
+		t.Errorf("C no debería ser ejecutable en LOCAL: %+v", desc)
+	}
+	if desc := tc.Describir("C", "X86"); !desc.Ejecutable || desc.Plataforma != "X86" || !desc.InterpretesDe[0].Usable {
+		t.Errorf("C debería ser ejecutable en X86: %+v", desc)
+	}
+}
@@ -0,0 +1,93 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"slices"
+	"sort"
+)
+
+// Diagnostico explica por qué un lenguaje no se puede ejecutar en una plataforma
+type Diagnostico struct {
+	Lenguaje    string
+	Plataforma  string
+	Ejecutable  bool
+	Alcanzables []string               // Lenguajes a los que se puede llevar el programa, ordenados
+	Bloqueadas  []HerramientaBloqueada // Herramientas que parten de un lenguaje alcanzable pero no sirven
+	Faltantes   []Interprete           // Definiciones que por sí solas harían ejecutable el lenguaje
+}
+
+// HerramientaBloqueada es una herramienta que existe pero está escrita en un lenguaje no ejecutable
+type HerramientaBloqueada struct {
+	Descripcion      string // Por ejemplo "el traductor de RUST de TS a JS"
+	LenguajeFaltante string // El lenguaje en el que está escrita y que no se puede ejecutar
+}
+
+// Diagnosticar revisa hasta dónde se puede llevar el lenguaje y qué falta para llegar a la
+// plataforma. Siempre basta con una definición (en el peor caso un intérprete del mismo
+// lenguaje en la plataforma), así que Faltantes lista todas las alternativas de una sola
+// definición. Si la plataforma no está definida no se sugiere nada.
+func (tc *Toolchain) Diagnosticar(lenguaje, plataforma string) Diagnostico {
+	lenguaje, plataforma = tc.NombreLenguaje(lenguaje), tc.NombreLenguaje(plataforma)
+	diag := Diagnostico{Lenguaje: lenguaje, Plataforma: plataforma, Ejecutable: tc.ejecutableEn(tc.estado, plataforma, lenguaje)}
+	if diag.Ejecutable {
+		return diag
+	}
+
+	// Un intérprete lleva al lenguaje en que está escrito y solo sirve si ese lenguaje se ejecuta
+	// en la plataforma; un traductor solo lleva a su destino si se puede correr, es decir, si su
+	// lenguaje base se ejecuta en alguna plataforma
+	alcanzables := map[string]bool{lenguaje: true}
+	cola := []string{lenguaje}
+	for len(cola) > 0 {
+		actual := cola[0]
+		cola = cola[1:]
+		for _, interp := range tc.interpretesPara(actual) {
+			if !tc.ejecutableEn(tc.estado, plataforma, interp.Lenguaje) {
+				diag.Bloqueadas = append(diag.Bloqueadas, HerramientaBloqueada{
+					Descripcion:      "el " + interp.String(),
+					LenguajeFaltante: interp.Lenguaje,
+				})
+			}
+			if !alcanzables[interp.Lenguaje] {
+				alcanzables[interp.Lenguaje] = true
+				cola = append(cola, interp.Lenguaje)
+			}
+		}
+		for _, trad := range tc.traductoresPara(actual) {
+			if !tc.estado.enAlguna[trad.LenguajeBase] {
+				diag.Bloqueadas = append(diag.Bloqueadas, HerramientaBloqueada{
+					Descripcion:      "el " + trad.String(),
+					LenguajeFaltante: trad.LenguajeBase,
+				})
+				continue
+			}
+			if !alcanzables[trad.LenguajeDestino] {
+				alcanzables[trad.LenguajeDestino] = true
+				cola = append(cola, trad.LenguajeDestino)
+			}
+		}
+	}
+	for l := range alcanzables {
+		diag.Alcanzables = append(diag.Alcanzables, l)
+	}
+	sort.Strings(diag.Alcanzables)
+	if !slices.Contains(tc.plataformas, plataforma) {
+		return diag
+	}
+
+	// Se prueba un intérprete en la plataforma para cada lenguaje que aparece en el diagnóstico,
+	// propagando sobre una copia del estado
+	candidatos := slices.Clone(diag.Alcanzables)
+	for _, b := range diag.Bloqueadas {
+		candidatos = append(candidatos, b.LenguajeFaltante)
+	}
+	sort.Strings(candidatos)
+	for _, candidato := range slices.Compact(candidatos) {
+		prueba := tc.estado.clonar()
+		tc.marcar(prueba, plataforma, candidato)
+		if tc.ejecutableEn(prueba, plataforma, lenguaje) {
+			diag.Faltantes = append(diag.Faltantes, interpreteDe(candidato, plataforma))
+		}
+	}
+	return diag
+}
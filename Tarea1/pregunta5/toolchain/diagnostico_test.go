@@ -0,0 +1,85 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"strings"
+	"testing"
+)
+
+// Prueba que el diagnóstico nombra lo alcanzable, lo bloqueado y lo que falta definir
+func TestDiagnosticar(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("JS", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("RUST", "TS", "JS")
+	tc.DefinirTraductor("JS", "TS", "ELM")
+	tc.DefinirInterprete("ELM", "HASKELL")
+
+	diag := tc.Diagnosticar("TS", LENGUAJE_LOCAL)
+	if diag.Ejecutable {
+		t.Fatalf("TS no debería ser ejecutable")
+	}
+	if strings.Join(diag.Alcanzables, ",") != "ELM,HASKELL,TS" {
+		t.Errorf("Alcanzables incorrectos: %v", diag.Alcanzables)
+	}
+	if len(diag.Bloqueadas) != 2 ||
+		diag.Bloqueadas[0].Descripcion != "el traductor de RUST de TS a JS" || diag.Bloqueadas[0].LenguajeFaltante != "RUST" ||
+		diag.Bloqueadas[1].Descripcion != "el intérprete de ELM en HASKELL" || diag.Bloqueadas[1].LenguajeFaltante != "HASKELL" {
+		t.Errorf("Herramientas bloqueadas incorrectas: %+v", diag.Bloqueadas)
+	}
+
+	var faltantes []string
+	for _, f := range diag.Faltantes {
+		faltantes = append(faltantes, f.LenguajeBase)
+	}
+	if strings.Join(faltantes, ",") != "ELM,HASKELL,RUST,TS" {
+		t.Errorf("Definiciones faltantes incorrectas: %v", faltantes)
+	}
+
+	// Aplicar cualquiera de las sugerencias vuelve ejecutable al lenguaje
+	tc.DefinirInterprete("RUST", LENGUAJE_LOCAL)
+	if diag := tc.Diagnosticar("TS", LENGUAJE_LOCAL); !diag.Ejecutable || diag.Alcanzables != nil {
+		t.Errorf("TS debería ser ejecutable después de definir RUST: %+v", diag)
+	}
+}
+
+// Prueba el diagnóstico de un lenguaje del que no se sabe nada
+func TestDiagnosticarDesconocido(t *testing.T) {
+	diag := New().Diagnosticar("COBOL", LENGUAJE_LOCAL)
+	if strings.Join(diag.Alcanzables, ",") != "COBOL" || len(diag.Bloqueadas) != 0 {
+		t.Errorf("Diagnóstico incorrecto: %+v", diag)
+	}
+	if len(diag.Faltantes) != 1 || diag.Faltantes[0].LenguajeBase != "COBOL" {
+		t.Errorf("La única sugerencia debería ser un intérprete de COBOL: %+v", diag.Faltantes)
+	}
+}
+
+// Prueba que el diagnóstico mira la plataforma pedida y no solo LOCAL
+func TestDiagnosticarEnPlataforma(t *testing.T) {
+	tc := New()
+	tc.DefinirPlataforma("X86")
+	tc.DefinirInterprete("C", "X86")
+
+	if diag := tc.Diagnosticar("C", "X86"); !diag.Ejecutable || diag.Plataforma != "X86" {
+		t.Errorf("C debería ser ejecutable en X86: %+v", diag)
+	}
+
+	diag := tc.Diagnosticar("C", LENGUAJE_LOCAL)
+	if diag.Ejecutable {
+		t.Fatalf("C no debería ser ejecutable en LOCAL")
+	}
+	if len(diag.Bloqueadas) != 1 || diag.Bloqueadas[0].LenguajeFaltante != "X86" {
+		t.Errorf("El intérprete escrito en X86 debería estar bloqueado en LOCAL: %+v", diag.Bloqueadas)
+	}
+	var faltantes []string
+	for _, f := range diag.Faltantes {
+		faltantes = append(faltantes, f.String())
+	}
+	if strings.Join(faltantes, ",") != "intérprete de C en LOCAL,intérprete de X86 en LOCAL" {
+		t.Errorf("Definiciones faltantes incorrectas: %v", faltantes)
+	}
+
+	// En una plataforma que no existe no hay nada que sugerir
+	if diag := tc.Diagnosticar("C", "ARM"); diag.Ejecutable || diag.Faltantes != nil {
+		t.Errorf("Diagnóstico en una plataforma no definida incorrecto: %+v", diag)
+	}
+}
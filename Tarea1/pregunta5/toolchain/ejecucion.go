@@ -0,0 +1,147 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Implementacion es el comportamiento real de una herramienta: recibe código y devuelve el
+// código equivalente en otro lenguaje. Un traductor de L a M devuelve código en M. Un intérprete
+// de L escrito en M se modela con su primera proyección de Futamura: dado el programa en L
+// devuelve un programa en M que hace lo mismo, que después se sigue llevando hasta LOCAL.
+type Implementacion func(codigo string) (string, error)
+
+// Regla reemplaza una palabra del código por otras; A puede tener varias palabras o ninguna
+type Regla struct {
+	De string
+	A  string
+}
+
+// Reescritura arma una implementación que reemplaza palabra por palabra según las reglas.
+// Las palabras sin regla quedan igual.
+func Reescritura(reglas ...Regla) Implementacion {
+	reemplazos := make(map[string]string, len(reglas))
+	for _, regla := range reglas {
+		reemplazos[regla.De] = regla.A
+	}
+	return func(codigo string) (string, error) {
+		var resultado []string
+		for _, palabra := range strings.Fields(codigo) {
+			if reemplazo, ok := reemplazos[palabra]; ok {
+				resultado = append(resultado, strings.Fields(reemplazo)...)
+			} else {
+				resultado = append(resultado, palabra)
+			}
+		}
+		return strings.Join(resultado, " "), nil
+	}
+}
+
+// DefinirCodigo guarda el código fuente de un programa ya definido
+func (tc *Toolchain) DefinirCodigo(nombre, codigo string) error {
+	prog, ok := tc.programas[nombre]
+	if !ok {
+		return noDefinido("el programa '%s' no estaba definido", nombre)
+	}
+	prog.Codigo = codigo
+	tc.programas[nombre] = prog
+	return nil
+}
+
+// ImplementarInterprete le da comportamiento real a un intérprete ya definido
+func (tc *Toolchain) ImplementarInterprete(lenguajeBase, lenguaje string, impl Implementacion) error {
+	interp, err := tc.buscarInterprete(lenguajeBase, lenguaje)
+	if err != nil {
+		return err
+	}
+	tc.implInterpretes[[2]string{interp.EspecificacionBase(), interp.Lenguaje}] = impl
+	return nil
+}
+
+// ImplementarTraductor le da comportamiento real a un traductor ya definido
+func (tc *Toolchain) ImplementarTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino string, impl Implementacion) error {
+	trad, err := tc.buscarTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino)
+	if err != nil {
+		return err
+	}
+	tc.implTraductores[[3]string{trad.LenguajeBase, trad.EspecificacionOrigen(), trad.LenguajeDestino}] = impl
+	return nil
+}
+
+// Etapa es el código de un programa en uno de los lenguajes de la ruta
+type Etapa struct {
+	Lenguaje string
+	Codigo   string
+}
+
+// Ejecucion es el resultado de correr un programa: las etapas por las que pasó su código y
+// los números que imprimió la máquina LOCAL
+type Ejecucion struct {
+	Etapas []Etapa
+	Salida []int64
+}
+
+// Ejecutar lleva el código del programa hasta LOCAL por la ruta más corta que use solo
+// herramientas con implementación y lo corre con la entrada dada. Si falla una herramienta
+// o la máquina devuelve las etapas hechas hasta ese momento junto con el error. Solo LOCAL
+// tiene una máquina que corra código, así que las demás plataformas no se ejecutan.
+func (tc *Toolchain) Ejecutar(nombre string, entrada []int64) (Ejecucion, error) {
+	prog, ok := tc.programas[nombre]
+	if !ok {
+		return Ejecucion{}, noDefinido("el programa '%s' no estaba definido", nombre)
+	}
+	if prog.Codigo == "" {
+		return Ejecucion{}, fmt.Errorf("el programa '%s' no tiene código", nombre)
+	}
+	pasos, ok := tc.rutaImplementada(prog.Lenguaje)
+	if !ok {
+		return Ejecucion{}, fmt.Errorf("no hay una ruta con herramientas implementadas de %s a LOCAL", prog.Lenguaje)
+	}
+
+	ejecucion := Ejecucion{Etapas: []Etapa{{Lenguaje: prog.Lenguaje, Codigo: prog.Codigo}}}
+	codigo := prog.Codigo
+	for _, paso := range pasos {
+		var err error
+		if codigo, err = paso.implementacion(codigo); err != nil {
+			return ejecucion, fmt.Errorf("falló el %s: %v", paso.herramienta, err)
+		}
+		ejecucion.Etapas = append(ejecucion.Etapas, Etapa{Lenguaje: paso.destino, Codigo: codigo})
+	}
+
+	salida, err := EjecutarLocal(codigo, entrada)
+	ejecucion.Salida = salida
+	if err != nil {
+		return ejecucion, fmt.Errorf("error en LOCAL: %v", err)
+	}
+	return ejecucion, nil
+}
+
+// rutaImplementada busca con BFS la ruta más corta hasta LOCAL que use solo herramientas
+// con implementación y devuelve sus pasos en orden
+func (tc *Toolchain) rutaImplementada(lenguaje string) ([]arista, bool) {
+	anterior := map[string]arista{}
+	visto := map[string]bool{lenguaje: true}
+	cola := []string{lenguaje}
+	for len(cola) > 0 {
+		actual := cola[0]
+		cola = cola[1:]
+		if actual == LENGUAJE_LOCAL {
+			var pasos []arista
+			for l := actual; l != lenguaje; {
+				paso := anterior[l]
+				pasos = append([]arista{paso}, pasos...)
+				l = paso.origen
+			}
+			return pasos, true
+		}
+		for _, a := range tc.aristasDesde(actual, LENGUAJE_LOCAL) {
+			if a.implementacion != nil && !visto[a.destino] {
+				visto[a.destino] = true
+				anterior[a.destino] = a
+				cola = append(cola, a.destino)
+			}
+		}
+	}
+	return nil, false
+}
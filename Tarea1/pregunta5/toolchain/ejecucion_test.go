@@ -0,0 +1,81 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Prueba un programa que pasa por un traductor y un intérprete antes de llegar a LOCAL
+func TestEjecutar(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("cuadrado", "ES")
+	tc.DefinirCodigo("cuadrado", "leer cuadrado imprimir")
+
+	// ES se traduce a FORTH con reglas y un intérprete de FORTH en LOCAL lo baja a la máquina
+	tc.DefinirInterprete("FORTH", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("FORTH", "ES", "FORTH")
+	if _, err := tc.Ejecutar("cuadrado", []int64{7}); err == nil || !strings.Contains(err.Error(), "no hay una ruta con herramientas implementadas") {
+		t.Errorf("Sin implementaciones no debería poder ejecutarse: %v", err)
+	}
+
+	tc.ImplementarTraductor("FORTH", "ES", "FORTH", Reescritura(Regla{"cuadrado", "dup *"}, Regla{"imprimir", "."}))
+	tc.ImplementarInterprete("FORTH", LENGUAJE_LOCAL, func(codigo string) (string, error) { return codigo, nil })
+
+	ejecucion, err := tc.Ejecutar("cuadrado", []int64{7})
+	if err != nil || !slices.Equal(ejecucion.Salida, []int64{49}) {
+		t.Fatalf("Ejecución incorrecta: %+v, %v", ejecucion, err)
+	}
+	esperado := []Etapa{
+		{Lenguaje: "ES", Codigo: "leer cuadrado imprimir"},
+		{Lenguaje: "FORTH", Codigo: "leer dup * ."},
+		{Lenguaje: LENGUAJE_LOCAL, Codigo: "leer dup * ."},
+	}
+	if !slices.Equal(ejecucion.Etapas, esperado) {
+		t.Errorf("Etapas incorrectas: %+v", ejecucion.Etapas)
+	}
+
+	// Al eliminar la herramienta se pierde también su implementación
+	tc.EliminarInterprete("FORTH", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("FORTH", LENGUAJE_LOCAL)
+	if _, err := tc.Ejecutar("cuadrado", []int64{7}); err == nil {
+		t.Errorf("La implementación debería haberse borrado con el intérprete")
+	}
+}
+
+// Prueba los errores al implementar y al ejecutar
+func TestEjecutarErrores(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("app", "X")
+	tc.DefinirPrograma("local", LENGUAJE_LOCAL)
+	tc.DefinirCodigo("local", "1 0 /")
+
+	if _, err := tc.Ejecutar("nada", nil); err == nil || !strings.Contains(err.Error(), "no estaba definido") {
+		t.Errorf("Error incorrecto para un programa desconocido: %v", err)
+	}
+	if _, err := tc.Ejecutar("app", nil); err == nil || !strings.Contains(err.Error(), "no tiene código") {
+		t.Errorf("Error incorrecto para un programa sin código: %v", err)
+	}
+	if _, err := tc.Ejecutar("local", nil); err == nil || !strings.Contains(err.Error(), "error en LOCAL: división por cero") {
+		t.Errorf("Error incorrecto de la máquina: %v", err)
+	}
+	if err := tc.DefinirCodigo("nada", "1"); err == nil {
+		t.Errorf("No debería poder dar código a un programa desconocido")
+	}
+	if err := tc.ImplementarInterprete("X", LENGUAJE_LOCAL, nil); err == nil {
+		t.Errorf("No debería poder implementar un intérprete no definido")
+	}
+	if err := tc.ImplementarTraductor("X", "X", LENGUAJE_LOCAL, nil); err == nil {
+		t.Errorf("No debería poder implementar un traductor no definido")
+	}
+
+	tc.DefinirCodigo("app", "1 .")
+	tc.DefinirInterprete("X", LENGUAJE_LOCAL)
+	tc.ImplementarInterprete("X", LENGUAJE_LOCAL, func(string) (string, error) { return "", errors.New("sintaxis") })
+	ejecucion, err := tc.Ejecutar("app", nil)
+	if err == nil || err.Error() != "falló el intérprete de X en LOCAL: sintaxis" || len(ejecucion.Etapas) != 1 {
+		t.Errorf("Error incorrecto de la herramienta: %v, %+v", err, ejecucion)
+	}
+}
@@ -0,0 +1,87 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import "slices"
+
+// EliminarPrograma borra un programa del catálogo. Ningún otro programa depende de él.
+func (tc *Toolchain) EliminarPrograma(nombre string) error {
+	if _, ok := tc.programas[nombre]; !ok {
+		return noDefinido("el programa '%s' no estaba definido", nombre)
+	}
+	delete(tc.programas, nombre)
+	return nil
+}
+
+// Afectado es un programa que dejó de ser ejecutable y las plataformas, en el orden en que se
+// declararon, donde antes se podía ejecutar y ya no
+type Afectado struct {
+	Programa
+	Plataformas []string
+}
+
+// EliminarInterprete borra un intérprete y devuelve, ordenados por nombre, los programas que
+// se podían ejecutar antes de borrarlo y ya no
+func (tc *Toolchain) EliminarInterprete(lenguajeBase, lenguaje string) ([]Afectado, error) {
+	interp, err := tc.buscarInterprete(lenguajeBase, lenguaje)
+	if err != nil {
+		return nil, err
+	}
+	tc.interpretes = quitar(tc.interpretes, interp)
+	tc.interpretesDe[interp.LenguajeBase] = quitar(tc.interpretesDe[interp.LenguajeBase], interp)
+	tc.interpretesEn[interp.Lenguaje] = quitar(tc.interpretesEn[interp.Lenguaje], interp)
+	delete(tc.implInterpretes, [2]string{interp.EspecificacionBase(), interp.Lenguaje})
+
+	if !tc.InterpreteUsable(interp) {
+		return nil, nil
+	}
+	return tc.recalcularYComparar(), nil
+}
+
+// EliminarTraductor borra un traductor y devuelve los programas que dejaron de ser ejecutables
+func (tc *Toolchain) EliminarTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino string) ([]Afectado, error) {
+	trad, err := tc.buscarTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino)
+	if err != nil {
+		return nil, err
+	}
+	tc.traductores = quitar(tc.traductores, trad)
+	tc.traductoresDesde[trad.LenguajeOrigen] = quitar(tc.traductoresDesde[trad.LenguajeOrigen], trad)
+	tc.traductoresSegunL[trad.LenguajeBase] = quitar(tc.traductoresSegunL[trad.LenguajeBase], trad)
+	if trad.LenguajeDestino != trad.LenguajeBase {
+		tc.traductoresSegunL[trad.LenguajeDestino] = quitar(tc.traductoresSegunL[trad.LenguajeDestino], trad)
+	}
+	delete(tc.implTraductores, [3]string{trad.LenguajeBase, trad.EspecificacionOrigen(), trad.LenguajeDestino})
+
+	if !tc.TraductorUsable(trad) {
+		return nil, nil
+	}
+	return tc.recalcularYComparar(), nil
+}
+
+// recalcularYComparar rehace el estado y devuelve los programas cuyo lenguaje dejó de ser
+// ejecutable en alguna plataforma. Una herramienta que no se podía usar no sostenía a
+// ningún lenguaje, así que solo hace falta llamarla al borrar una que sí se podía usar.
+func (tc *Toolchain) recalcularYComparar() []Afectado {
+	antes := tc.estado
+	tc.recalcularEjecutables()
+	var afectados []Afectado
+	for _, prog := range tc.Programas() {
+		var perdidas []string
+		for _, p := range tc.plataformas {
+			if antes.es(p, prog.Lenguaje) && !tc.estado.es(p, prog.Lenguaje) {
+				perdidas = append(perdidas, p)
+			}
+		}
+		if len(perdidas) > 0 {
+			afectados = append(afectados, Afectado{Programa: prog, Plataformas: perdidas})
+		}
+	}
+	return afectados
+}
+
+// quitar borra la primera aparición de v en la lista
+func quitar[T comparable](lista []T, v T) []T {
+	if i := slices.Index(lista, v); i >= 0 {
+		return slices.Delete(lista, i, i+1)
+	}
+	return lista
+}
@@ -0,0 +1,63 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"strings"
+	"testing"
+)
+
+// Prueba que eliminar una herramienta reporta los programas que pierden la ruta
+func TestEliminarHerramientas(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("web", "TS")
+	tc.DefinirPrograma("script", "JS")
+	tc.DefinirPrograma("nativo", "C")
+	tc.DefinirInterprete("JS", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("C", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("JS", "TS", "JS")
+	tc.DefinirTraductor("C", "TS", "C")
+
+	// TS todavía tiene otra ruta a través de C
+	afectados, err := tc.EliminarTraductor("JS", "TS", "JS")
+	if err != nil || len(afectados) != 0 {
+		t.Errorf("Ningún programa debería verse afectado: %v, %v", afectados, err)
+	}
+
+	// Sin el intérprete de C se pierden TS (el traductor está escrito en C) y C
+	afectados, err = tc.EliminarInterprete("C", LENGUAJE_LOCAL)
+	var nombres []string
+	for _, prog := range afectados {
+		nombres = append(nombres, prog.Nombre)
+	}
+	if err != nil || strings.Join(nombres, ",") != "nativo,web" {
+		t.Errorf("Programas afectados incorrectos: %v, %v", nombres, err)
+	}
+	if !tc.EsEjecutable("JS") || len(tc.Interpretes()) != 1 || len(tc.Traductores()) != 1 {
+		t.Errorf("Se eliminó más de lo pedido: %v %v", tc.Interpretes(), tc.Traductores())
+	}
+
+	if _, err := tc.EliminarInterprete("C", LENGUAJE_LOCAL); err == nil || !strings.Contains(err.Error(), "no estaba definido") {
+		t.Errorf("Eliminar dos veces debería fallar: %v", err)
+	}
+	if _, err := tc.EliminarTraductor("JS", "TS", "JS"); !EsNoDefinido(err) {
+		t.Errorf("Eliminar dos veces debería fallar por no estar definido: %v", err)
+	}
+	if _, err := tc.EliminarInterprete("Python>=x", LENGUAJE_LOCAL); err == nil || EsNoDefinido(err) {
+		t.Errorf("Una versión mal escrita no debería confundirse con algo no definido: %v", err)
+	}
+}
+
+// Prueba que eliminar un programa lo quita del catálogo
+func TestEliminarPrograma(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("app", "GO")
+	if err := tc.EliminarPrograma("app"); err != nil {
+		t.Fatalf("Error al eliminar: %v", err)
+	}
+	if _, ok := tc.Programa("app"); ok {
+		t.Errorf("El programa sigue definido")
+	}
+	if err := tc.EliminarPrograma("app"); err == nil || !strings.Contains(err.Error(), "no estaba definido") {
+		t.Errorf("Eliminar dos veces debería fallar: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpcionesExportar indica qué resaltar al exportar el grafo del catálogo
+type OpcionesExportar struct {
+	Ruta []string // Lenguajes de una ruta a resaltar, por ejemplo la de RutaMasCorta; vacía si no hay
+}
+
+// aristaExportada es una herramienta vista como arista del grafo de lenguajes
+type aristaExportada struct {
+	origen, destino string
+	etiqueta        string
+	usable          bool // Falso si la herramienta está escrita en un lenguaje no ejecutable
+	resaltada       bool // Verdadero si une dos lenguajes consecutivos de la ruta
+}
+
+// grafoExportado arma los nodos (lenguajes con sus programas) y las aristas a dibujar.
+// Si hay varias herramientas entre dos lenguajes consecutivos de la ruta se resaltan todas
+// las que se pueden usar, porque la ruta solo dice por qué lenguajes pasa.
+func (tc *Toolchain) grafoExportado(opciones OpcionesExportar) ([]string, map[string][]string, []aristaExportada) {
+	programas := make(map[string][]string)
+	lenguajes := make(map[string]bool)
+	for _, plataforma := range tc.plataformas {
+		lenguajes[plataforma] = true
+	}
+	for _, prog := range tc.Programas() {
+		lenguajes[prog.Lenguaje] = true
+		programas[prog.Lenguaje] = append(programas[prog.Lenguaje], prog.Nombre)
+	}
+
+	var pasos [][2]string
+	for i := 0; i+1 < len(opciones.Ruta); i++ {
+		pasos = append(pasos, [2]string{tc.NombreLenguaje(opciones.Ruta[i]), tc.NombreLenguaje(opciones.Ruta[i+1])})
+	}
+	// enRuta indica si algún paso de la ruta sale de un lenguaje aceptado y llega al destino
+	enRuta := func(acepta func(string) bool, destino string) bool {
+		for _, paso := range pasos {
+			if paso[1] == destino && acepta(paso[0]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var aristas []aristaExportada
+	for _, interp := range tc.interpretes {
+		usable := tc.InterpreteUsable(interp)
+		aristas = append(aristas, aristaExportada{
+			origen:    interp.EspecificacionBase(),
+			destino:   interp.Lenguaje,
+			etiqueta:  fmt.Sprintf("intérprete, costo %g", interp.Costo),
+			usable:    usable,
+			resaltada: usable && enRuta(interp.acepta, interp.Lenguaje),
+		})
+	}
+	for _, trad := range tc.traductores {
+		usable := tc.TraductorUsable(trad)
+		aristas = append(aristas, aristaExportada{
+			origen:    trad.EspecificacionOrigen(),
+			destino:   trad.LenguajeDestino,
+			etiqueta:  fmt.Sprintf("traductor en %s, costo %g", trad.LenguajeBase, trad.Costo),
+			usable:    usable,
+			resaltada: usable && enRuta(trad.acepta, trad.LenguajeDestino),
+		})
+	}
+	for _, a := range aristas {
+		lenguajes[a.origen] = true
+		lenguajes[a.destino] = true
+	}
+
+	nodos := make([]string, 0, len(lenguajes))
+	for l := range lenguajes {
+		nodos = append(nodos, l)
+	}
+	sort.Strings(nodos)
+	return nodos, programas, aristas
+}
+
+// ExportarDOT escribe el catálogo como un grafo de Graphviz. Los lenguajes son nodos, con
+// sus programas debajo del nombre; las herramientas que no se pueden usar salen en gris.
+func (tc *Toolchain) ExportarDOT(w io.Writer, opciones OpcionesExportar) error {
+	nodos, programas, aristas := tc.grafoExportado(opciones)
+	enRuta := make(map[string]bool)
+	for _, l := range opciones.Ruta {
+		enRuta[l] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph toolchain {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, l := range nodos {
+		etiqueta := strings.Join(append([]string{l}, programas[l]...), "\n")
+		atributos := []string{"label=" + strconv.Quote(etiqueta)}
+		if slices.Contains(tc.plataformas, l) {
+			atributos = append(atributos, "shape=doubleoctagon")
+		}
+		if enRuta[l] {
+			atributos = append(atributos, "color=red", "penwidth=2")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", strconv.Quote(l), strings.Join(atributos, ", "))
+	}
+	for _, a := range aristas {
+		atributos := []string{"label=" + strconv.Quote(a.etiqueta)}
+		if !a.usable {
+			atributos = append(atributos, "color=gray", "fontcolor=gray", "style=dashed")
+		}
+		if a.resaltada {
+			atributos = append(atributos, "color=red", "penwidth=2")
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", strconv.Quote(a.origen), strconv.Quote(a.destino), strings.Join(atributos, ", "))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportarMermaid escribe el catálogo como un diagrama de flujo de Mermaid con las mismas
+// convenciones que ExportarDOT
+func (tc *Toolchain) ExportarMermaid(w io.Writer, opciones OpcionesExportar) error {
+	nodos, programas, aristas := tc.grafoExportado(opciones)
+	ids := make(map[string]string)
+	for i, l := range nodos {
+		ids[l] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, l := range nodos {
+		etiqueta := strings.Join(append([]string{l}, programas[l]...), "<br/>")
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[l], escaparMermaid(etiqueta))
+	}
+	var grises, resaltadas []string
+	for i, a := range aristas {
+		flecha := "-->"
+		if !a.usable {
+			flecha = "-.->"
+			grises = append(grises, strconv.Itoa(i))
+		}
+		if a.resaltada {
+			resaltadas = append(resaltadas, strconv.Itoa(i))
+		}
+		fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", ids[a.origen], flecha, escaparMermaid(a.etiqueta), ids[a.destino])
+	}
+	if len(grises) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#999,color:#999\n", strings.Join(grises, ","))
+	}
+	if len(resaltadas) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:red,stroke-width:3px\n", strings.Join(resaltadas, ","))
+	}
+	var enRuta []string
+	for _, l := range opciones.Ruta {
+		if id, ok := ids[l]; ok {
+			enRuta = append(enRuta, id)
+		}
+	}
+	if len(enRuta) > 0 {
+		b.WriteString("  classDef ruta stroke:red,stroke-width:3px\n")
+		fmt.Fprintf(&b, "  class %s ruta\n", strings.Join(enRuta, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escaparMermaid evita que las comillas corten la etiqueta
+func escaparMermaid(texto string) string {
+	return strings.ReplaceAll(texto, `"`, "#quot;")
+}
@@ -0,0 +1,76 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"bytes"
+	"testing"
+)
+
+// catalogoDeEjemplo tiene una herramienta usable y otra escrita en un lenguaje no ejecutable
+func catalogoDeEjemplo() *Toolchain {
+	tc := New()
+	tc.DefinirPrograma("app", "TS")
+	tc.DefinirInterprete("JS", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("JS", "TS", "JS")
+	tc.DefinirTraductor("RUST", "TS", "JS")
+	return tc
+}
+
+// Prueba la exportación a DOT con la ruta resaltada y la herramienta bloqueada en gris
+func TestExportarDOT(t *testing.T) {
+	tc := catalogoDeEjemplo()
+	ruta, _ := tc.RutaMasCorta("TS")
+
+	var out bytes.Buffer
+	if err := tc.ExportarDOT(&out, OpcionesExportar{Ruta: ruta.Lenguajes}); err != nil {
+		t.Fatalf("Error al exportar: %v", err)
+	}
+	esperado := `digraph toolchain {
+  rankdir=LR;
+  node [shape=box];
+  "JS" [label="JS", color=red, penwidth=2];
+  "LOCAL" [label="LOCAL", shape=doubleoctagon, color=red, penwidth=2];
+  "TS" [label="TS\napp", color=red, penwidth=2];
+  "JS" -> "LOCAL" [label="intérprete, costo 1", color=red, penwidth=2];
+  "TS" -> "JS" [label="traductor en JS, costo 1", color=red, penwidth=2];
+  "TS" -> "JS" [label="traductor en RUST, costo 1", color=gray, fontcolor=gray, style=dashed];
+}
+`
+	if out.String() != esperado {
+		t.Errorf("DOT incorrecto:\n%s", out.String())
+	}
+}
+
+// Prueba la exportación a Mermaid sin ruta y con ruta
+func TestExportarMermaid(t *testing.T) {
+	tc := catalogoDeEjemplo()
+
+	var out bytes.Buffer
+	tc.ExportarMermaid(&out, OpcionesExportar{})
+	esperado := `graph LR
+  n0["JS"]
+  n1["LOCAL"]
+  n2["TS<br/>app"]
+  n0 -->|"intérprete, costo 1"| n1
+  n2 -->|"traductor en JS, costo 1"| n0
+  n2 -.->|"traductor en RUST, costo 1"| n0
+  linkStyle 2 stroke:#999,color:#999
+`
+	if out.String() != esperado {
+		t.Errorf("Mermaid sin ruta incorrecto:\n%s", out.String())
+	}
+
+	out.Reset()
+	ruta, _ := tc.RutaMasCorta("TS")
+	tc.ExportarMermaid(&out, OpcionesExportar{Ruta: ruta.Lenguajes})
+	if !bytes.Contains(out.Bytes(), []byte("  linkStyle 0,1 stroke:red,stroke-width:3px\n  classDef ruta stroke:red,stroke-width:3px\n  class n2,n0,n1 ruta\n")) {
+		t.Errorf("Mermaid con ruta incorrecto:\n%s", out.String())
+	}
+
+	// Una ruta sin lenguajes del grafo no deja una línea class vacía
+	out.Reset()
+	tc.ExportarMermaid(&out, OpcionesExportar{Ruta: []string{"ZZ"}})
+	if out.String() != esperado {
+		t.Errorf("Mermaid con una ruta ajena incorrecto:\n%s", out.String())
+	}
+}
@@ -0,0 +1,133 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// puntoFijoIngenuo recalcula los lenguajes ejecutables recorriendo todo el catálogo hasta
+// que no cambie nada; sirve de referencia para el cálculo incremental
+func puntoFijoIngenuo(tc *Toolchain) map[string]bool {
+	ejecutables := map[string]bool{LENGUAJE_LOCAL: true}
+	for cambio := true; cambio; {
+		cambio = false
+		for _, interp := range tc.Interpretes() {
+			if !ejecutables[interp.LenguajeBase] && ejecutables[interp.Lenguaje] {
+				ejecutables[interp.LenguajeBase] = true
+				cambio = true
+			}
+		}
+		for _, trad := range tc.Traductores() {
+			if !ejecutables[trad.LenguajeOrigen] && ejecutables[trad.LenguajeBase] && ejecutables[trad.LenguajeDestino] {
+				ejecutables[trad.LenguajeOrigen] = true
+				cambio = true
+			}
+		}
+	}
+	return ejecutables
+}
+
+// Prueba con catálogos aleatorios que el conjunto incremental coincide con el punto fijo
+// después de cada definición y cada eliminación
+func TestEjecutablesIncrementales(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	lenguaje := func() string {
+		if rng.Intn(8) == 0 {
+			return LENGUAJE_LOCAL
+		}
+		return fmt.Sprintf("L%d", rng.Intn(12))
+	}
+
+	for ronda := 0; ronda < 50; ronda++ {
+		tc := New()
+		for paso := 0; paso < 80; paso++ {
+			switch rng.Intn(4) {
+			case 0:
+				tc.DefinirInterprete(lenguaje(), lenguaje())
+			case 1:
+				tc.DefinirTraductor(lenguaje(), lenguaje(), lenguaje())
+			case 2:
+				if interpretes := tc.Interpretes(); len(interpretes) > 0 {
+					interp := interpretes[rng.Intn(len(interpretes))]
+					tc.EliminarInterprete(interp.LenguajeBase, interp.Lenguaje)
+				}
+			case 3:
+				if traductores := tc.Traductores(); len(traductores) > 0 {
+					trad := traductores[rng.Intn(len(traductores))]
+					tc.EliminarTraductor(trad.LenguajeBase, trad.LenguajeOrigen, trad.LenguajeDestino)
+				}
+			}
+
+			esperado := puntoFijoIngenuo(tc)
+			for _, l := range tc.Lenguajes() {
+				if tc.EsEjecutable(l) != esperado[l] {
+					t.Fatalf("Ronda %d, paso %d: EsEjecutable(%s) = %v, el punto fijo dice %v", ronda, paso, l, tc.EsEjecutable(l), esperado[l])
+				}
+			}
+		}
+	}
+}
+
+// catalogoGrande arma un catálogo con cerca de 3 herramientas por lenguaje. Las herramientas se
+// definen de la cadena más lejana hacia LOCAL, así casi todo se vuelve ejecutable al final.
+func catalogoGrande(lenguajes int) *Toolchain {
+	tc := New()
+	nombre := func(i int) string {
+		if i < 0 {
+			return LENGUAJE_LOCAL
+		}
+		return fmt.Sprintf("L%d", i)
+	}
+	for i := lenguajes - 1; i >= 0; i-- {
+		tc.DefinirInterpreteConCosto(nombre(i), nombre(i-1), float64(1+i%5))
+		tc.DefinirTraductorConCosto(nombre(i/2-1), nombre(i), nombre(i/3-1), float64(1+i%3))
+		tc.DefinirTraductorConCosto(nombre(i-2), nombre(i), nombre(i/7-1), 10)
+	}
+	return tc
+}
+
+// Arma un catálogo de unas 12000 herramientas
+func BenchmarkDefinirCatalogoGrande(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		catalogoGrande(4000)
+	}
+}
+
+// Consulta de ejecutabilidad, que ya no recorre el catálogo
+func BenchmarkEsEjecutable(b *testing.B) {
+	tc := catalogoGrande(4000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.EsEjecutable(fmt.Sprintf("L%d", i%4000))
+	}
+}
+
+// Ruta con menos pasos desde el lenguaje más lejano
+func BenchmarkRutaMasCorta(b *testing.B) {
+	tc := catalogoGrande(4000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.RutaMasCorta("L3999")
+	}
+}
+
+// Ruta más barata desde el lenguaje más lejano
+func BenchmarkRutaMasBarata(b *testing.B) {
+	tc := catalogoGrande(4000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.RutaMasBarata("L3999")
+	}
+}
+
+// Eliminar y volver a definir una herramienta que se está usando obliga a recalcular
+func BenchmarkEliminarYDefinir(b *testing.B) {
+	tc := catalogoGrande(4000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.EliminarInterprete("L2000", "L1999")
+		tc.DefinirInterpreteConCosto("L2000", "L1999", 1)
+	}
+}
@@ -0,0 +1,100 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EjecutarLocal corre código del lenguaje LOCAL, una pequeña máquina de pila. El código es una
+// lista de palabras separadas por espacios:
+//
+//	<entero>           apila el número
+//	+ - * / mod        sacan dos números y apilan el resultado (división entera)
+//	dup drop swap over manejo de la pila como en Forth
+//	.                  saca el tope y lo agrega a la salida
+//	leer               apila el siguiente número de la entrada
+//
+// No hay saltos, así que todo programa termina. Devuelve los números impresos en orden.
+func EjecutarLocal(codigo string, entrada []int64) ([]int64, error) {
+	var pila, salida []int64
+	sacar := func(palabra string, n int) ([]int64, error) {
+		if len(pila) < n {
+			return nil, fmt.Errorf("pila vacía en '%s'", palabra)
+		}
+		valores := pila[len(pila)-n:]
+		pila = pila[:len(pila)-n]
+		return append([]int64(nil), valores...), nil
+	}
+
+	for _, palabra := range strings.Fields(codigo) {
+		if n, err := strconv.ParseInt(palabra, 10, 64); err == nil {
+			pila = append(pila, n)
+			continue
+		}
+
+		switch palabra {
+		case "+", "-", "*", "/", "mod":
+			v, err := sacar(palabra, 2)
+			if err != nil {
+				return salida, err
+			}
+			a, b := v[0], v[1]
+			switch palabra {
+			case "+":
+				pila = append(pila, a+b)
+			case "-":
+				pila = append(pila, a-b)
+			case "*":
+				pila = append(pila, a*b)
+			default:
+				if b == 0 {
+					return salida, fmt.Errorf("división por cero")
+				}
+				if palabra == "/" {
+					pila = append(pila, a/b)
+				} else {
+					pila = append(pila, a%b)
+				}
+			}
+		case "dup":
+			v, err := sacar(palabra, 1)
+			if err != nil {
+				return salida, err
+			}
+			pila = append(pila, v[0], v[0])
+		case "drop":
+			if _, err := sacar(palabra, 1); err != nil {
+				return salida, err
+			}
+		case "swap":
+			v, err := sacar(palabra, 2)
+			if err != nil {
+				return salida, err
+			}
+			pila = append(pila, v[1], v[0])
+		case "over":
+			v, err := sacar(palabra, 2)
+			if err != nil {
+				return salida, err
+			}
+			pila = append(pila, v[0], v[1], v[0])
+		case ".":
+			v, err := sacar(palabra, 1)
+			if err != nil {
+				return salida, err
+			}
+			salida = append(salida, v[0])
+		case "leer":
+			if len(entrada) == 0 {
+				return salida, fmt.Errorf("no hay más entrada")
+			}
+			pila = append(pila, entrada[0])
+			entrada = entrada[1:]
+		default:
+			return salida, fmt.Errorf("palabra desconocida '%s'", palabra)
+		}
+	}
+	return salida, nil
+}
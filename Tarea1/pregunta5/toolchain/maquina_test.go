@@ -0,0 +1,51 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Prueba las palabras de la máquina de pila de LOCAL
+func TestEjecutarLocal(t *testing.T) {
+	casos := []struct {
+		codigo   string
+		entrada  []int64
+		esperado []int64
+	}{
+		{"2 3 + .", nil, []int64{5}},
+		{"10 4 - . 6 7 * . 17 5 / . 17 5 mod .", nil, []int64{6, 42, 3, 2}},
+		{"leer dup * .", []int64{9}, []int64{81}},
+		{"1 2 swap . . 1 2 over . . . 5 drop", nil, []int64{1, 2, 1, 2, 1}},
+		{"", nil, nil},
+	}
+	for _, caso := range casos {
+		salida, err := EjecutarLocal(caso.codigo, caso.entrada)
+		if err != nil || !slices.Equal(salida, caso.esperado) {
+			t.Errorf("%q: obtuve %v, %v; esperaba %v", caso.codigo, salida, err, caso.esperado)
+		}
+	}
+}
+
+// Prueba los errores de la máquina de pila
+func TestEjecutarLocalErrores(t *testing.T) {
+	casos := []struct {
+		codigo   string
+		esperado string
+	}{
+		{"1 +", "pila vacía en '+'"},
+		{"1 0 /", "división por cero"},
+		{"leer", "no hay más entrada"},
+		{"1 . hola", "palabra desconocida 'hola'"},
+	}
+	for _, caso := range casos {
+		salida, err := EjecutarLocal(caso.codigo, nil)
+		if err == nil || !strings.Contains(err.Error(), caso.esperado) {
+			t.Errorf("%q: esperaba un error con '%s', obtuve %v", caso.codigo, caso.esperado, err)
+		}
+		if caso.codigo == "1 . hola" && !slices.Equal(salida, []int64{1}) {
+			t.Errorf("Se debería conservar lo impreso antes del error: %v", salida)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Normalizacion decide cuándo dos nombres de lenguaje se refieren al mismo lenguaje
+type Normalizacion int
+
+const (
+	IgnorarMayusculas Normalizacion = iota // "python" y "Python" son el mismo lenguaje
+	NombresExactos                         // Solo son el mismo lenguaje si se escriben igual
+)
+
+// clave da la forma del nombre con la que se comparan los lenguajes
+func (n Normalizacion) clave(nombre string) string {
+	if n == IgnorarMayusculas {
+		return strings.ToUpper(nombre)
+	}
+	return nombre
+}
+
+// String da el nombre de la política, el mismo que acepta LeerNormalizacion
+func (n Normalizacion) String() string {
+	if n == NombresExactos {
+		return "exactos"
+	}
+	return "mayusculas"
+}
+
+// LeerNormalizacion interpreta el nombre de una política, sin importar mayúsculas
+func LeerNormalizacion(texto string) (Normalizacion, error) {
+	switch strings.ToLower(texto) {
+	case "mayusculas":
+		return IgnorarMayusculas, nil
+	case "exactos":
+		return NombresExactos, nil
+	}
+	return 0, fmt.Errorf("normalización '%s' desconocida (usa mayusculas o exactos)", texto)
+}
+
+// Alias es otro nombre para un lenguaje, por ejemplo "py" para "Python"
+type Alias struct {
+	Nombre   string
+	Lenguaje string
+}
+
+// Normalizacion devuelve la política de nombres del catálogo
+func (tc *Toolchain) Normalizacion() Normalizacion {
+	return tc.normalizacion
+}
+
+// UsarNormalizacion cambia la política de nombres. Solo se puede con el catálogo vacío, porque
+// al cambiarla dos lenguajes ya definidos podrían pasar a ser el mismo.
+func (tc *Toolchain) UsarNormalizacion(n Normalizacion) error {
+	if len(tc.programas) > 0 || len(tc.interpretes) > 0 || len(tc.traductores) > 0 || len(tc.alias) > 0 || len(tc.plataformas) > 1 {
+		return fmt.Errorf("la normalización solo se puede cambiar con el catálogo vacío")
+	}
+	tc.normalizacion = n
+	tc.nombres = map[string]string{n.clave(LENGUAJE_LOCAL): LENGUAJE_LOCAL}
+	tc.definidos = map[string]bool{n.clave(LENGUAJE_LOCAL): true}
+	return nil
+}
+
+// DefinirAlias hace que alias se refiera al lenguaje dado en todas las operaciones del catálogo.
+// El alias no puede ser el nombre de un lenguaje que ya se usó ni estar definido dos veces.
+func (tc *Toolchain) DefinirAlias(alias, lenguaje string) error {
+	if _, resto := separarNombre(alias); resto != "" {
+		return fmt.Errorf("el alias %s no puede llevar versión", alias)
+	}
+	if _, resto := separarNombre(lenguaje); resto != "" {
+		return fmt.Errorf("el alias tiene que ser de un lenguaje sin versión")
+	}
+	clave := tc.normalizacion.clave(alias)
+	if anterior, ok := tc.alias[clave]; ok {
+		return fmt.Errorf("el alias %s ya estaba definido para %s", anterior.Nombre, anterior.Lenguaje)
+	}
+	if nombre, ok := tc.nombres[clave]; ok {
+		return fmt.Errorf("%s ya es el nombre de un lenguaje", nombre)
+	}
+	destino := tc.registrarLenguaje(lenguaje)
+	if tc.normalizacion.clave(destino) == clave {
+		return fmt.Errorf("un lenguaje no puede ser alias de sí mismo")
+	}
+	tc.alias[clave] = Alias{Nombre: alias, Lenguaje: destino}
+	return nil
+}
+
+// Alias devuelve los alias definidos, ordenados por nombre
+func (tc *Toolchain) Alias() []Alias {
+	lista := make([]Alias, 0, len(tc.alias))
+	for _, a := range tc.alias {
+		lista = append(lista, a)
+	}
+	slices.SortFunc(lista, func(a, b Alias) int { return strings.Compare(a.Nombre, b.Nombre) })
+	return lista
+}
+
+// NombreLenguaje da el nombre con el que el catálogo conoce al lenguaje: resuelve los alias y,
+// si la política lo permite, la forma en que lo escribió su definición o, si todavía no tiene,
+// la primera vez que se usó. Un nombre que todavía no se usó se devuelve igual. La versión o
+// el rango, si hay, se dejan como están.
+func (tc *Toolchain) NombreLenguaje(nombre string) string {
+	nombre, resto := separarNombre(nombre)
+	clave := tc.normalizacion.clave(nombre)
+	if a, ok := tc.alias[clave]; ok {
+		return a.Lenguaje + resto
+	}
+	if conocido, ok := tc.nombres[clave]; ok {
+		return conocido + resto
+	}
+	return nombre + resto
+}
+
+// registrarLenguaje es como NombreLenguaje pero además recuerda la forma de un nombre nuevo;
+// se usa al definir, para que las consultas no cambien el catálogo
+func (tc *Toolchain) registrarLenguaje(nombre string) string {
+	canonico := tc.NombreLenguaje(nombre)
+	clave := tc.normalizacion.clave(canonico)
+	if _, ok := tc.nombres[clave]; !ok {
+		tc.nombres[clave] = canonico
+	}
+	return canonico
+}
+
+// definirLenguaje fija la forma del lenguaje con la que lo escribe una definición: lo que
+// acepta un intérprete o un traductor, o una plataforma. Antes el lenguaje pudo aparecer solo
+// como referencia (el de un programa, en el que está hecha una herramienta o al que traduce);
+// si ahí se escribió distinto se renombra en todo el catálogo. Un alias o un lenguaje que ya
+// tiene definición no cambian.
+func (tc *Toolchain) definirLenguaje(espec string) {
+	nombre, _ := separarNombre(espec)
+	clave := tc.normalizacion.clave(nombre)
+	if _, ok := tc.alias[clave]; ok || tc.definidos[clave] {
+		return
+	}
+	tc.definidos[clave] = true
+	if anterior, ok := tc.nombres[clave]; ok && anterior != nombre {
+		tc.renombrar(anterior, nombre)
+	}
+}
+
+// renombrar cambia cómo se escribe un lenguaje, con todas sus versiones, en las definiciones,
+// los alias y las implementaciones, y rehace los índices y el estado
+func (tc *Toolchain) renombrar(anterior, nuevo string) {
+	cambiar := func(espec string) string {
+		if nombre, resto := separarNombre(espec); nombre == anterior {
+			return nuevo + resto
+		}
+		return espec
+	}
+
+	tc.nombres[tc.normalizacion.clave(nuevo)] = nuevo
+	for nombre, prog := range tc.programas {
+		prog.Lenguaje = cambiar(prog.Lenguaje)
+		tc.programas[nombre] = prog
+	}
+	for i, interp := range tc.interpretes {
+		tc.interpretes[i].LenguajeBase, tc.interpretes[i].Lenguaje = cambiar(interp.LenguajeBase), cambiar(interp.Lenguaje)
+	}
+	for i, trad := range tc.traductores {
+		tc.traductores[i].LenguajeBase = cambiar(trad.LenguajeBase)
+		tc.traductores[i].LenguajeOrigen = cambiar(trad.LenguajeOrigen)
+		tc.traductores[i].LenguajeDestino = cambiar(trad.LenguajeDestino)
+	}
+	for i, plataforma := range tc.plataformas {
+		tc.plataformas[i] = cambiar(plataforma)
+	}
+	for clave, a := range tc.alias {
+		a.Lenguaje = cambiar(a.Lenguaje)
+		tc.alias[clave] = a
+	}
+	if nodos, ok := tc.nodosDe[anterior]; ok {
+		delete(tc.nodosDe, anterior)
+		for i, nodo := range nodos {
+			nodos[i] = cambiar(nodo)
+		}
+		tc.nodosDe[nuevo] = nodos
+	}
+	implInterpretes := make(map[[2]string]Implementacion, len(tc.implInterpretes))
+	for clave, impl := range tc.implInterpretes {
+		implInterpretes[[2]string{cambiar(clave[0]), cambiar(clave[1])}] = impl
+	}
+	tc.implInterpretes = implInterpretes
+	implTraductores := make(map[[3]string]Implementacion, len(tc.implTraductores))
+	for clave, impl := range tc.implTraductores {
+		implTraductores[[3]string{cambiar(clave[0]), cambiar(clave[1]), cambiar(clave[2])}] = impl
+	}
+	tc.implTraductores = implTraductores
+
+	clear(tc.interpretesDe)
+	clear(tc.interpretesEn)
+	clear(tc.traductoresDesde)
+	clear(tc.traductoresSegunL)
+	for _, interp := range tc.interpretes {
+		tc.indexarInterprete(interp)
+	}
+	for _, trad := range tc.traductores {
+		tc.indexarTraductor(trad)
+	}
+	tc.recalcularEjecutables()
+}
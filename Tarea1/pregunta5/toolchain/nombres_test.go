@@ -0,0 +1,172 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Prueba que por defecto los nombres de lenguaje no distinguen mayúsculas y que se muestra la
+// forma de la definición del lenguaje, o la del primer uso si todavía no tiene definición
+func TestNombresSinMayusculas(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("foo", "python")
+	tc.DefinirPrograma("bar", "rust")
+	tc.DefinirTraductor("RUST", "Python", "local")
+
+	if prog, _ := tc.Programa("foo"); prog.Lenguaje != "Python" {
+		t.Errorf("Se esperaba la forma de la definición del lenguaje, obtuve %s", prog.Lenguaje)
+	}
+	if prog, _ := tc.Programa("bar"); prog.Lenguaje != "rust" {
+		t.Errorf("Un lenguaje sin definición debería conservar su primera forma, obtuve %s", prog.Lenguaje)
+	}
+	trad := tc.Traductores()[0]
+	if trad.LenguajeBase != "rust" || trad.LenguajeOrigen != "Python" || trad.LenguajeDestino != LENGUAJE_LOCAL {
+		t.Errorf("El traductor no usó los nombres conocidos: %v", trad)
+	}
+
+	tc.DefinirInterprete("Rust", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("PYTHON", "rust")
+	if prog, _ := tc.Programa("bar"); prog.Lenguaje != "Rust" || tc.Traductores()[0].LenguajeBase != "Rust" {
+		t.Errorf("La definición de Rust debería renombrar sus usos anteriores: %s, %v", prog.Lenguaje, tc.Traductores()[0])
+	}
+	if interp := tc.Interpretes()[1]; interp.LenguajeBase != "Python" || interp.Lenguaje != "Rust" {
+		t.Errorf("Una segunda definición no debería cambiar la forma: %v", interp)
+	}
+	if !tc.EsEjecutable("PYTHON") {
+		t.Errorf("python, Python y PYTHON deberían ser el mismo lenguaje")
+	}
+	if ruta, ok := tc.RutaMasCorta("python"); !ok || strings.Join(ruta.Lenguajes, " -> ") != "Python -> LOCAL" {
+		t.Errorf("Ruta incorrecta: %v, %v", ruta.Lenguajes, ok)
+	}
+	if err := tc.DefinirInterprete("RUST", "Local"); err == nil {
+		t.Errorf("El intérprete repetido con otras mayúsculas no se detectó")
+	}
+}
+
+// Prueba la política de nombres exactos y que solo se puede elegir con el catálogo vacío
+func TestNombresExactos(t *testing.T) {
+	tc := New()
+	if err := tc.UsarNormalizacion(NombresExactos); err != nil {
+		t.Fatalf("Error al cambiar la normalización: %v", err)
+	}
+	tc.DefinirPrograma("foo", "python")
+	tc.DefinirInterprete("Python", LENGUAJE_LOCAL)
+	if tc.EsEjecutable("python") {
+		t.Errorf("Con nombres exactos python y Python son lenguajes distintos")
+	}
+	if err := tc.UsarNormalizacion(IgnorarMayusculas); err == nil {
+		t.Errorf("No se debería poder cambiar la normalización con definiciones")
+	}
+
+	if _, err := LeerNormalizacion("rara"); err == nil {
+		t.Errorf("Se aceptó una normalización desconocida")
+	}
+	if n, err := LeerNormalizacion("EXACTOS"); err != nil || n != NombresExactos {
+		t.Errorf("LeerNormalizacion(EXACTOS) = %v, %v", n, err)
+	}
+}
+
+// Prueba que los alias se aplican en las definiciones, las consultas y las rutas
+func TestAlias(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("Python", LENGUAJE_LOCAL)
+	if err := tc.DefinirAlias("py", "python"); err != nil {
+		t.Fatalf("Error al definir el alias: %v", err)
+	}
+	tc.DefinirPrograma("app", "PY")
+	tc.DefinirTraductor("py", "Cython", "py")
+
+	if prog, _ := tc.Programa("app"); prog.Lenguaje != "Python" {
+		t.Errorf("El alias no se resolvió al definir el programa: %s", prog.Lenguaje)
+	}
+	if trad := tc.Traductores()[0]; trad.LenguajeBase != "Python" || trad.LenguajeDestino != "Python" {
+		t.Errorf("El alias no se resolvió en el traductor: %v", trad)
+	}
+	if ruta, ok := tc.RutaMasCorta("cython"); !ok || strings.Join(ruta.Lenguajes, " -> ") != "Cython -> Python -> LOCAL" {
+		t.Errorf("Ruta con alias incorrecta: %v, %v", ruta.Lenguajes, ok)
+	}
+	if desc := tc.Describir("Py", LENGUAJE_LOCAL); desc.Lenguaje != "Python" || len(desc.Programas) != 1 {
+		t.Errorf("Describir no resolvió el alias: %+v", desc)
+	}
+
+	errores := []struct {
+		alias, lenguaje, mensaje string
+	}{
+		{"PY", "Ruby", "ya estaba definido"},
+		{"cython", "Python", "ya es el nombre de un lenguaje"},
+		{"rb", "RB", "alias de sí mismo"},
+	}
+	for _, caso := range errores {
+		if err := tc.DefinirAlias(caso.alias, caso.lenguaje); err == nil || !strings.Contains(err.Error(), caso.mensaje) {
+			t.Errorf("DefinirAlias(%s, %s) = %v, se esperaba un error con '%s'", caso.alias, caso.lenguaje, err, caso.mensaje)
+		}
+	}
+	if alias := tc.Alias(); len(alias) != 1 || alias[0] != (Alias{Nombre: "py", Lenguaje: "Python"}) {
+		t.Errorf("Alias incorrectos: %v", alias)
+	}
+}
+
+// Prueba que la normalización y los alias se guardan y se cargan con el catálogo
+func TestGuardarNombres(t *testing.T) {
+	tc := New()
+	tc.UsarNormalizacion(NombresExactos)
+	tc.DefinirAlias("js", "JavaScript")
+	tc.DefinirPrograma("web", "js")
+
+	var buf bytes.Buffer
+	tc.Guardar(&buf)
+	cargado, err := Cargar(&buf)
+	if err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+	if cargado.Normalizacion() != NombresExactos {
+		t.Errorf("No se conservó la normalización")
+	}
+	if cargado.NombreLenguaje("js") != "JavaScript" || cargado.NombreLenguaje("JS") != "JS" {
+		t.Errorf("No se conservó el alias con nombres exactos")
+	}
+
+	_, err = Cargar(strings.NewReader(`{"version": 1, "normalizacion": "rara", "programas": [], "interpretes": [], "traductores": []}`))
+	if err == nil {
+		t.Errorf("Se cargó un catálogo con una normalización desconocida")
+	}
+}
+
+// Prueba que la definición renombra los usos anteriores de un lenguaje con versión y que se
+// avisa qué versiones se ejecutan aunque el nombre solo no lo haga
+func TestNombreDeLaDefinicionConVersiones(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("mi app", "python@3.11")
+	tc.DefinirTraductor("python@3.11", "c", "x86")
+	tc.ImplementarTraductor("python@3.11", "c", "x86", Reescritura())
+	tc.DefinirPlataforma("X86")
+	tc.DefinirInterprete("Python>=3.8", LENGUAJE_LOCAL)
+
+	if prog, _ := tc.Programa("mi app"); prog.Lenguaje != "Python@3.11" {
+		t.Errorf("El programa debería usar la forma de la definición: %s", prog.Lenguaje)
+	}
+	if !slices.Equal(tc.Plataformas(), []string{LENGUAJE_LOCAL, "X86"}) {
+		t.Errorf("La plataforma debería usar la forma de su definición: %v", tc.Plataformas())
+	}
+	ruta, ok := tc.RutaMasCortaEn("c", "X86")
+	if !ok || strings.Join(ruta.Lenguajes, " -> ") != "c -> X86" || ruta.Herramientas[0] != "traductor de Python@3.11 de c a X86 (corre en LOCAL)" {
+		t.Errorf("Ruta renombrada incorrecta: %+v, %v", ruta, ok)
+	}
+	if tc.implTraductores[[3]string{"Python@3.11", "c", "X86"}] == nil {
+		t.Errorf("La implementación del traductor se perdió al renombrar")
+	}
+	if _, err := tc.EliminarTraductor("PYTHON@3.11", "C", "X86"); err != nil {
+		t.Errorf("No se encontró el traductor con los nombres nuevos: %v", err)
+	}
+
+	desc := tc.Describir("python", LENGUAJE_LOCAL)
+	if desc.Lenguaje != "Python" || desc.Ejecutable || !slices.Equal(desc.Versiones, []string{"Python@3.11"}) {
+		t.Errorf("Describir(python) incorrecto: %+v", desc)
+	}
+	if desc := tc.Describir("Python@3.11", LENGUAJE_LOCAL); !desc.Ejecutable || desc.Versiones != nil {
+		t.Errorf("Describir(Python@3.11) incorrecto: %+v", desc)
+	}
+}
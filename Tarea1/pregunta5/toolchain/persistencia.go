@@ -0,0 +1,211 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// VERSION_FORMATO es la versión del formato de archivo que se escribe y se acepta al cargar
+const VERSION_FORMATO = 1
+
+// archivoCatalogo es el formato JSON de un catálogo guardado:
+//
+//	{
+//	  "version": 1,
+//	  "normalizacion": "exactos",
+//	  "plataformas": ["X86", "ARM"],
+//	  "alias": [{"nombre": "ts", "lenguaje": "TS"}],
+//	  "programas":   [{"nombre": "app", "lenguaje": "TS", "codigo": "2 3 + ."}],
+//	  "interpretes": [{"lenguaje_base": "JS", "lenguaje": "LOCAL", "costo": 1}],
+//	  "traductores": [{"lenguaje_base": "JS", "lenguaje_origen": "TS", "lenguaje_destino": "JS", "costo": 1}]
+//	}
+//
+// El costo es opcional y vale COSTO_POR_DEFECTO si no aparece, y el código de los programas
+// y las plataformas aparte de LOCAL también son opcionales. Sin normalización se ignoran las
+// mayúsculas y sin alias no hay otros nombres para los lenguajes. Las implementaciones de
+// las herramientas son funciones de Go y no se guardan. Los intérpretes y traductores se
+// guardan en el orden en que se definieron, así las rutas no cambian al cargar el archivo.
+type archivoCatalogo struct {
+	Version       int                  `json:"version"`
+	Normalizacion string               `json:"normalizacion,omitempty"`
+	Plataformas   []string             `json:"plataformas,omitempty"`
+	Alias         []aliasGuardado      `json:"alias,omitempty"`
+	Programas     []programaGuardado   `json:"programas"`
+	Interpretes   []interpreteGuardado `json:"interpretes"`
+	Traductores   []traductorGuardado  `json:"traductores"`
+}
+
+type aliasGuardado struct {
+	Nombre   string `json:"nombre"`
+	Lenguaje string `json:"lenguaje"`
+}
+
+type programaGuardado struct {
+	Nombre   string `json:"nombre"`
+	Lenguaje string `json:"lenguaje"`
+	Codigo   string `json:"codigo,omitempty"`
+}
+
+type interpreteGuardado struct {
+	LenguajeBase string   `json:"lenguaje_base"`
+	Lenguaje     string   `json:"lenguaje"`
+	Costo        *float64 `json:"costo,omitempty"`
+}
+
+type traductorGuardado struct {
+	LenguajeBase    string   `json:"lenguaje_base"`
+	LenguajeOrigen  string   `json:"lenguaje_origen"`
+	LenguajeDestino string   `json:"lenguaje_destino"`
+	Costo           *float64 `json:"costo,omitempty"`
+}
+
+// Guardar escribe el catálogo en formato JSON
+func (tc *Toolchain) Guardar(w io.Writer) error {
+	archivo := archivoCatalogo{
+		Version:     VERSION_FORMATO,
+		Programas:   []programaGuardado{},
+		Interpretes: []interpreteGuardado{},
+		Traductores: []traductorGuardado{},
+		Plataformas: tc.plataformas[1:],
+	}
+	if tc.normalizacion != IgnorarMayusculas {
+		archivo.Normalizacion = tc.normalizacion.String()
+	}
+	for _, a := range tc.Alias() {
+		archivo.Alias = append(archivo.Alias, aliasGuardado{Nombre: a.Nombre, Lenguaje: a.Lenguaje})
+	}
+	for _, prog := range tc.Programas() {
+		archivo.Programas = append(archivo.Programas, programaGuardado{Nombre: prog.Nombre, Lenguaje: prog.Lenguaje, Codigo: prog.Codigo})
+	}
+	for _, interp := range tc.interpretes {
+		costo := interp.Costo
+		archivo.Interpretes = append(archivo.Interpretes, interpreteGuardado{LenguajeBase: interp.EspecificacionBase(), Lenguaje: interp.Lenguaje, Costo: &costo})
+	}
+	for _, trad := range tc.traductores {
+		costo := trad.Costo
+		archivo.Traductores = append(archivo.Traductores, traductorGuardado{
+			LenguajeBase:    trad.LenguajeBase,
+			LenguajeOrigen:  trad.EspecificacionOrigen(),
+			LenguajeDestino: trad.LenguajeDestino,
+			Costo:           &costo,
+		})
+	}
+
+	encoder := NuevoCodificador(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archivo)
+}
+
+// NuevoCodificador arma el codificador JSON que usan el archivo de catálogo y la API, sin
+// escapar < y > para que los rangos como Python>=3.8 se lean tal cual
+func NuevoCodificador(w io.Writer) *json.Encoder {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder
+}
+
+// Cargar lee un catálogo en formato JSON. Si el archivo tiene un campo desconocido, una
+// versión distinta, datos vacíos o definiciones repetidas devuelve error y ningún catálogo.
+func Cargar(r io.Reader) (*Toolchain, error) {
+	var archivo archivoCatalogo
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&archivo); err != nil {
+		return nil, fmt.Errorf("formato de catálogo inválido: %v", err)
+	}
+	if archivo.Version != VERSION_FORMATO {
+		return nil, fmt.Errorf("versión de catálogo %d no soportada (se esperaba %d)", archivo.Version, VERSION_FORMATO)
+	}
+
+	tc := New()
+	if archivo.Normalizacion != "" {
+		normalizacion, err := LeerNormalizacion(archivo.Normalizacion)
+		if err != nil {
+			return nil, err
+		}
+		if err := tc.UsarNormalizacion(normalizacion); err != nil {
+			return nil, fmt.Errorf("normalización '%s': %v", archivo.Normalizacion, err)
+		}
+	}
+	for i, plataforma := range archivo.Plataformas {
+		if plataforma == "" {
+			return nil, fmt.Errorf("la plataforma %d no tiene nombre", i+1)
+		}
+		if err := tc.DefinirPlataforma(plataforma); err != nil {
+			return nil, err
+		}
+	}
+	for i, a := range archivo.Alias {
+		if a.Nombre == "" || a.Lenguaje == "" {
+			return nil, fmt.Errorf("al alias %d le falta el nombre o el lenguaje", i+1)
+		}
+		if err := tc.DefinirAlias(a.Nombre, a.Lenguaje); err != nil {
+			return nil, err
+		}
+	}
+	for i, prog := range archivo.Programas {
+		if prog.Nombre == "" || prog.Lenguaje == "" {
+			return nil, fmt.Errorf("el programa %d no tiene nombre o lenguaje", i+1)
+		}
+		if _, ok := tc.programas[prog.Nombre]; ok {
+			return nil, fmt.Errorf("el programa '%s' está repetido", prog.Nombre)
+		}
+		if err := tc.DefinirPrograma(prog.Nombre, prog.Lenguaje); err != nil {
+			return nil, fmt.Errorf("programa '%s': %v", prog.Nombre, err)
+		}
+		if err := tc.DefinirCodigo(prog.Nombre, prog.Codigo); err != nil {
+			return nil, fmt.Errorf("programa '%s': %v", prog.Nombre, err)
+		}
+	}
+	for i, interp := range archivo.Interpretes {
+		if interp.LenguajeBase == "" || interp.Lenguaje == "" {
+			return nil, fmt.Errorf("al intérprete %d le faltan lenguajes", i+1)
+		}
+		if err := tc.DefinirInterpreteConCosto(interp.LenguajeBase, interp.Lenguaje, costoGuardado(interp.Costo)); err != nil {
+			return nil, err
+		}
+	}
+	for i, trad := range archivo.Traductores {
+		if trad.LenguajeBase == "" || trad.LenguajeOrigen == "" || trad.LenguajeDestino == "" {
+			return nil, fmt.Errorf("al traductor %d le faltan lenguajes", i+1)
+		}
+		if err := tc.DefinirTraductorConCosto(trad.LenguajeBase, trad.LenguajeOrigen, trad.LenguajeDestino, costoGuardado(trad.Costo)); err != nil {
+			return nil, err
+		}
+	}
+	return tc, nil
+}
+
+// costoGuardado devuelve el costo del archivo o el costo por defecto si no venía
+func costoGuardado(costo *float64) float64 {
+	if costo == nil {
+		return COSTO_POR_DEFECTO
+	}
+	return *costo
+}
+
+// GuardarArchivo escribe el catálogo en la ruta dada, reemplazando el archivo si ya existía
+func (tc *Toolchain) GuardarArchivo(ruta string) error {
+	archivo, err := os.Create(ruta)
+	if err != nil {
+		return err
+	}
+	if err := tc.Guardar(archivo); err != nil {
+		archivo.Close()
+		return err
+	}
+	return archivo.Close()
+}
+
+// CargarArchivo lee un catálogo guardado con GuardarArchivo
+func CargarArchivo(ruta string) (*Toolchain, error) {
+	archivo, err := os.Open(ruta)
+	if err != nil {
+		return nil, err
+	}
+	defer archivo.Close()
+	return Cargar(archivo)
+}
@@ -0,0 +1,73 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Prueba que guardar y cargar un catálogo conserva todas las definiciones y su orden
+func TestGuardarYCargar(t *testing.T) {
+	tc := New()
+	tc.DefinirPrograma("app", "TS")
+	tc.DefinirPrograma("calc", "PY")
+	tc.DefinirInterpreteConCosto("JS", LENGUAJE_LOCAL, 2.5)
+	tc.DefinirInterprete("PY", "JS")
+	tc.DefinirTraductorConCosto("JS", "TS", "JS", 0)
+
+	ruta := filepath.Join(t.TempDir(), "catalogo.json")
+	if err := tc.GuardarArchivo(ruta); err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+	cargado, err := CargarArchivo(ruta)
+	if err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+
+	var original, copia bytes.Buffer
+	tc.Guardar(&original)
+	cargado.Guardar(&copia)
+	if original.String() != copia.String() {
+		t.Errorf("El catálogo cargado es distinto al guardado:\n%s\n%s", original.String(), copia.String())
+	}
+	if ruta, ok := cargado.RutaMasBarata("TS"); !ok || ruta.Costo != 2.5 {
+		t.Errorf("Los costos no se conservaron: %+v", ruta)
+	}
+}
+
+// Prueba que el costo es opcional en el archivo
+func TestCargarCostoPorDefecto(t *testing.T) {
+	tc, err := Cargar(strings.NewReader(`{"version": 1, "interpretes": [{"lenguaje_base": "GO", "lenguaje": "LOCAL"}]}`))
+	if err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+	if interpretes := tc.Interpretes(); len(interpretes) != 1 || interpretes[0].Costo != COSTO_POR_DEFECTO {
+		t.Errorf("El intérprete debería tener el costo por defecto: %+v", interpretes)
+	}
+}
+
+// Prueba que los archivos inválidos se rechazan con un mensaje claro
+func TestCargarErrores(t *testing.T) {
+	casos := []struct {
+		json     string
+		esperado string
+	}{
+		{`{"version": 1`, "formato de catálogo inválido"},
+		{`{"version": 2}`, "versión de catálogo 2 no soportada"},
+		{`{"version": 1, "programa": []}`, "formato de catálogo inválido"},
+		{`{"version": 1, "programas": [{"nombre": "a"}]}`, "no tiene nombre o lenguaje"},
+		{`{"version": 1, "programas": [{"nombre": "a", "lenguaje": "C"}, {"nombre": "a", "lenguaje": "GO"}]}`, "está repetido"},
+		{`{"version": 1, "interpretes": [{"lenguaje_base": "C", "lenguaje": "LOCAL", "costo": -1}]}`, "el costo no puede ser negativo"},
+		{`{"version": 1, "traductores": [{"lenguaje_base": "C", "lenguaje_origen": "C"}]}`, "le faltan lenguajes"},
+	}
+	for _, caso := range casos {
+		if _, err := Cargar(strings.NewReader(caso.json)); err == nil || !strings.Contains(err.Error(), caso.esperado) {
+			t.Errorf("Para %s esperaba un error con '%s', obtuve: %v", caso.json, caso.esperado, err)
+		}
+	}
+	if _, err := CargarArchivo(filepath.Join(t.TempDir(), "no_existe.json")); err == nil {
+		t.Errorf("Cargar un archivo que no existe debería fallar")
+	}
+}
@@ -0,0 +1,187 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Prueba que cada plataforma ejecuta su propio lenguaje y que no se pueden repetir
+func TestDefinirPlataforma(t *testing.T) {
+	tc := New()
+	if err := tc.DefinirPlataforma("ARM"); err != nil {
+		t.Fatalf("Error al definir ARM: %v", err)
+	}
+	if err := tc.DefinirPlataforma("ARM"); !EsRepetido(err) {
+		t.Errorf("No se detectó la plataforma repetida: %v", err)
+	}
+	if err := tc.DefinirPlataforma(LENGUAJE_LOCAL); err == nil {
+		t.Errorf("LOCAL ya es una plataforma y no debería poder definirse otra vez")
+	}
+	if !slices.Equal(tc.Plataformas(), []string{LENGUAJE_LOCAL, "ARM"}) {
+		t.Errorf("Plataformas incorrectas: %v", tc.Plataformas())
+	}
+	if !tc.EsEjecutableEn("ARM", "ARM") || tc.EsEjecutable("ARM") {
+		t.Errorf("ARM debería ejecutarse solo en ARM")
+	}
+}
+
+// Prueba la compilación cruzada: un compilador que corre en X86 genera código para ARM
+func TestCompilacionCruzada(t *testing.T) {
+	tc := New()
+	tc.DefinirTraductor("GO", "C", "ARM")
+	tc.DefinirInterprete("GO", "X86")
+	if tc.EsEjecutableEn("C", "ARM") {
+		t.Errorf("C no debería ser ejecutable antes de declarar las plataformas")
+	}
+
+	// Las plataformas se pueden declarar después de las herramientas
+	tc.DefinirPlataforma("X86")
+	tc.DefinirPlataforma("ARM")
+	if !tc.EsEjecutableEn("C", "ARM") || tc.EsEjecutableEn("C", "X86") || tc.EsEjecutable("C") {
+		t.Errorf("C debería ejecutarse solo en ARM")
+	}
+	if !tc.EsEjecutableEn("GO", "X86") || tc.EsEjecutableEn("GO", "ARM") {
+		t.Errorf("GO debería ejecutarse solo en X86")
+	}
+
+	ruta, ok := tc.RutaMasCortaEn("C", "ARM")
+	if !ok || strings.Join(ruta.Lenguajes, " -> ") != "C -> ARM" {
+		t.Fatalf("Ruta de C a ARM incorrecta: %+v, %v", ruta, ok)
+	}
+	if !slices.Equal(ruta.Herramientas, []string{"traductor de GO de C a ARM (corre en X86)"}) || !slices.Equal(ruta.CorreEn, []string{"X86"}) {
+		t.Errorf("No se indicó dónde corre el traductor: %v, %v", ruta.Herramientas, ruta.CorreEn)
+	}
+	ruta, _ = tc.RutaMasBarataEn("GO", "X86")
+	if !slices.Equal(ruta.Herramientas, []string{"intérprete de GO en X86"}) || !slices.Equal(ruta.CorreEn, []string{""}) {
+		t.Errorf("Herramientas de la ruta a X86 incorrectas: %v, %q", ruta.Herramientas, ruta.CorreEn)
+	}
+	rutas := tc.TodasLasRutas("C", OpcionesRutas{Plataforma: "ARM"})
+	if len(rutas) != 1 || !slices.Equal(rutas[0].Herramientas, []string{"traductor de GO de C a ARM (corre en X86)"}) || !slices.Equal(rutas[0].CorreEn, []string{"X86"}) {
+		t.Errorf("TodasLasRutas hacia ARM incorrecto: %+v", rutas)
+	}
+	if _, ok := tc.RutaMasCorta("C"); ok {
+		t.Errorf("No debería haber ruta de C a LOCAL")
+	}
+
+	// Sin el intérprete en X86 el compilador ya no corre en ninguna parte
+	tc.DefinirPrograma("app", "C")
+	afectados, _ := tc.EliminarInterprete("GO", "X86")
+	if len(afectados) != 1 || afectados[0].Nombre != "app" || !slices.Equal(afectados[0].Plataformas, []string{"ARM"}) {
+		t.Errorf("Se esperaba que app dejara de ser ejecutable solo en ARM: %v", afectados)
+	}
+	if tc.EsEjecutableEn("C", "ARM") {
+		t.Errorf("C no debería ejecutarse en ARM sin un lugar donde correr el compilador")
+	}
+}
+
+// Prueba que las plataformas se guardan y se cargan con el catálogo
+func TestGuardarPlataformas(t *testing.T) {
+	tc := New()
+	tc.DefinirPlataforma("ARM")
+	tc.DefinirInterprete("PY", "ARM")
+
+	var buf bytes.Buffer
+	tc.Guardar(&buf)
+	if !strings.Contains(buf.String(), `"plataformas": [`) {
+		t.Errorf("No se guardaron las plataformas:\n%s", buf.String())
+	}
+	cargado, err := Cargar(&buf)
+	if err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+	if !cargado.EsEjecutableEn("PY", "ARM") {
+		t.Errorf("PY debería seguir siendo ejecutable en ARM al cargar")
+	}
+
+	// Un catálogo sin plataformas no escribe el campo
+	buf.Reset()
+	New().Guardar(&buf)
+	if strings.Contains(buf.String(), "plataformas") {
+		t.Errorf("Un catálogo sin plataformas no debería guardar el campo:\n%s", buf.String())
+	}
+}
+
+// puntoFijoPorPlataforma calcula a fuerza bruta qué lenguajes se ejecutan en cada plataforma
+func puntoFijoPorPlataforma(tc *Toolchain) map[string]map[string]bool {
+	en := make(map[string]map[string]bool)
+	for _, p := range tc.Plataformas() {
+		en[p] = map[string]bool{p: true}
+	}
+	enAlguna := func(l string) bool {
+		for _, lenguajes := range en {
+			if lenguajes[l] {
+				return true
+			}
+		}
+		return false
+	}
+	for cambio := true; cambio; {
+		cambio = false
+		for p, lenguajes := range en {
+			for _, interp := range tc.Interpretes() {
+				if !lenguajes[interp.LenguajeBase] && lenguajes[interp.Lenguaje] {
+					lenguajes[interp.LenguajeBase] = true
+					cambio = true
+				}
+			}
+			for _, trad := range tc.Traductores() {
+				if !lenguajes[trad.LenguajeOrigen] && enAlguna(trad.LenguajeBase) && lenguajes[trad.LenguajeDestino] {
+					en[p][trad.LenguajeOrigen] = true
+					cambio = true
+				}
+			}
+		}
+	}
+	return en
+}
+
+// Prueba con catálogos aleatorios de varias plataformas que el cálculo incremental coincide
+// con el punto fijo en cada plataforma
+func TestPlataformasIncrementales(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	plataformas := []string{LENGUAJE_LOCAL, "X86", "ARM"}
+	lenguaje := func() string {
+		if rng.Intn(6) == 0 {
+			return plataformas[rng.Intn(len(plataformas))]
+		}
+		return fmt.Sprintf("L%d", rng.Intn(10))
+	}
+
+	for ronda := 0; ronda < 50; ronda++ {
+		tc := New()
+		for paso := 0; paso < 60; paso++ {
+			switch rng.Intn(5) {
+			case 0:
+				tc.DefinirInterprete(lenguaje(), lenguaje())
+			case 1:
+				tc.DefinirTraductor(lenguaje(), lenguaje(), lenguaje())
+			case 2:
+				tc.DefinirPlataforma(plataformas[1+rng.Intn(2)])
+			case 3:
+				if interpretes := tc.Interpretes(); len(interpretes) > 0 {
+					interp := interpretes[rng.Intn(len(interpretes))]
+					tc.EliminarInterprete(interp.LenguajeBase, interp.Lenguaje)
+				}
+			case 4:
+				if traductores := tc.Traductores(); len(traductores) > 0 {
+					trad := traductores[rng.Intn(len(traductores))]
+					tc.EliminarTraductor(trad.LenguajeBase, trad.LenguajeOrigen, trad.LenguajeDestino)
+				}
+			}
+
+			esperado := puntoFijoPorPlataforma(tc)
+			for _, p := range tc.Plataformas() {
+				for _, l := range tc.Lenguajes() {
+					if tc.EsEjecutableEn(l, p) != esperado[p][l] {
+						t.Fatalf("Ronda %d, paso %d: EsEjecutableEn(%s, %s) = %v, el punto fijo dice %v", ronda, paso, l, p, tc.EsEjecutableEn(l, p), esperado[p][l])
+					}
+				}
+			}
+		}
+	}
+}
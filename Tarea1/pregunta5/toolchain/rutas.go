@@ -0,0 +1,306 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+// Ruta es una forma de llevar un lenguaje hasta una plataforma
+type Ruta struct {
+	Lenguajes    []string // Lenguajes por los que pasa, del inicial a la plataforma
+	Herramientas []string // La herramienta usada en cada paso; un traductor que corre en otra plataforma lo dice
+	CorreEn      []string // Por paso, la plataforma donde corre un traductor de compilación cruzada, o ""
+	Costo        float64  // Suma de los costos de las herramientas usadas
+}
+
+// arista es un paso de la ruta: usar una herramienta para pasar de un lenguaje a otro
+type arista struct {
+	origen         string
+	destino        string
+	costo          float64
+	herramienta    fmt.Stringer   // El intérprete o traductor que hace el paso
+	corre          string         // Plataforma donde corre un traductor si no es la de destino
+	implementacion Implementacion // Su comportamiento real, si se registró uno
+}
+
+// descripcion nombra la herramienta del paso y, en compilación cruzada, dónde corre
+func (a arista) descripcion() string {
+	if a.corre != "" {
+		return fmt.Sprintf("%s (corre en %s)", a.herramienta, a.corre)
+	}
+	return a.herramienta.String()
+}
+
+// RutaEjecucion busca la ruta más corta (menos herramientas) del lenguaje dado hasta LOCAL.
+// Entre rutas del mismo largo gana la que usa las herramientas definidas primero.
+func (tc *Toolchain) RutaEjecucion(lenguaje string) ([]string, bool) {
+	ruta, ok := tc.RutaMasCorta(lenguaje)
+	return ruta.Lenguajes, ok
+}
+
+// RutaMasCorta busca con BFS la ruta con menos pasos hasta LOCAL
+func (tc *Toolchain) RutaMasCorta(lenguaje string) (Ruta, bool) {
+	return tc.RutaMasCortaEn(lenguaje, LENGUAJE_LOCAL)
+}
+
+// RutaMasCortaEn busca con BFS la ruta con menos pasos hasta la plataforma. Solo recorre la
+// parte del grafo alcanzable desde el lenguaje.
+func (tc *Toolchain) RutaMasCortaEn(lenguaje, plataforma string) (Ruta, bool) {
+	lenguaje, plataforma = tc.NombreLenguaje(lenguaje), tc.NombreLenguaje(plataforma)
+	anterior := map[string]arista{lenguaje: {}}
+	cola := []string{lenguaje}
+
+	for len(cola) > 0 {
+		actual := cola[0]
+		cola = cola[1:]
+		if actual == plataforma {
+			return reconstruirRuta(anterior, lenguaje, plataforma), true
+		}
+		for _, a := range tc.aristasDesde(actual, plataforma) {
+			if _, visto := anterior[a.destino]; !visto {
+				anterior[a.destino] = a
+				cola = append(cola, a.destino)
+			}
+		}
+	}
+	return Ruta{}, false
+}
+
+// pendiente es un lenguaje en la cola de prioridad de Dijkstra
+type pendiente struct {
+	lenguaje  string
+	distancia float64
+}
+
+// colaPrioridad ordena por distancia y rompe los empates por nombre para que sea determinista
+type colaPrioridad []pendiente
+
+func (c colaPrioridad) Len() int { return len(c) }
+func (c colaPrioridad) Less(i, j int) bool {
+	if c[i].distancia != c[j].distancia {
+		return c[i].distancia < c[j].distancia
+	}
+	return c[i].lenguaje < c[j].lenguaje
+}
+func (c colaPrioridad) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c *colaPrioridad) Push(x any)   { *c = append(*c, x.(pendiente)) }
+func (c *colaPrioridad) Pop() any {
+	viejo := *c
+	ultimo := viejo[len(viejo)-1]
+	*c = viejo[:len(viejo)-1]
+	return ultimo
+}
+
+// RutaMasBarata busca con Dijkstra la ruta de menor costo total hasta LOCAL
+func (tc *Toolchain) RutaMasBarata(lenguaje string) (Ruta, bool) {
+	return tc.RutaMasBarataEn(lenguaje, LENGUAJE_LOCAL)
+}
+
+// RutaMasBarataEn busca con Dijkstra la ruta de menor costo total hasta la plataforma
+func (tc *Toolchain) RutaMasBarataEn(lenguaje, plataforma string) (Ruta, bool) {
+	lenguaje, plataforma = tc.NombreLenguaje(lenguaje), tc.NombreLenguaje(plataforma)
+	distancia := map[string]float64{lenguaje: 0}
+	anterior := map[string]arista{lenguaje: {}}
+	listo := make(map[string]bool)
+	cola := &colaPrioridad{{lenguaje: lenguaje}}
+
+	for cola.Len() > 0 {
+		actual := heap.Pop(cola).(pendiente)
+		if listo[actual.lenguaje] {
+			continue // Entrada vieja, ya se llegó a este lenguaje por un camino más barato
+		}
+		if actual.lenguaje == plataforma {
+			return reconstruirRuta(anterior, lenguaje, plataforma), true
+		}
+		listo[actual.lenguaje] = true
+
+		for _, a := range tc.aristasDesde(actual.lenguaje, plataforma) {
+			nueva := actual.distancia + a.costo
+			if d, ok := distancia[a.destino]; !listo[a.destino] && (!ok || nueva < d) {
+				distancia[a.destino] = nueva
+				anterior[a.destino] = a
+				heap.Push(cola, pendiente{lenguaje: a.destino, distancia: nueva})
+			}
+		}
+	}
+	return Ruta{}, false
+}
+
+// reconstruirRuta recorre los anteriores desde el destino hasta el origen
+func reconstruirRuta(anterior map[string]arista, origen, destino string) Ruta {
+	ruta := Ruta{Lenguajes: []string{destino}}
+	for actual := destino; actual != origen; {
+		paso := anterior[actual]
+		ruta.Costo += paso.costo
+		actual = paso.origen
+		ruta.Lenguajes = append(ruta.Lenguajes, actual)
+		ruta.Herramientas = append(ruta.Herramientas, paso.descripcion())
+		ruta.CorreEn = append(ruta.CorreEn, paso.corre)
+	}
+	slices.Reverse(ruta.Lenguajes)
+	slices.Reverse(ruta.Herramientas)
+	slices.Reverse(ruta.CorreEn)
+	return ruta
+}
+
+// aristasDesde da los pasos posibles desde un lenguaje hacia la plataforma usando solo
+// herramientas que se pueden usar: primero los intérpretes y después los traductores, cada
+// grupo en orden de definición
+func (tc *Toolchain) aristasDesde(lenguaje, plataforma string) []arista {
+	var aristas []arista
+	for _, interp := range tc.interpretesPara(lenguaje) {
+		if interpreteUsable(tc.estado, plataforma, interp) {
+			aristas = append(aristas, arista{
+				origen:         lenguaje,
+				destino:        interp.Lenguaje,
+				costo:          interp.Costo,
+				herramienta:    interp,
+				implementacion: tc.implInterpretes[[2]string{interp.EspecificacionBase(), interp.Lenguaje}],
+			})
+		}
+	}
+	for _, trad := range tc.traductoresPara(lenguaje) {
+		if traductorUsable(tc.estado, plataforma, trad) {
+			aristas = append(aristas, arista{
+				origen:         lenguaje,
+				destino:        trad.LenguajeDestino,
+				costo:          trad.Costo,
+				herramienta:    trad,
+				corre:          tc.dondeCorre(trad.LenguajeBase, plataforma),
+				implementacion: tc.implTraductores[[3]string{trad.LenguajeBase, trad.EspecificacionOrigen(), trad.LenguajeDestino}],
+			})
+		}
+	}
+	return aristas
+}
+
+// dondeCorre elige la plataforma en la que corre un traductor escrito en el lenguaje dado: la
+// de destino si se puede y si no la primera declarada donde se ejecute. Devuelve "" en el
+// primer caso, porque no hay compilación cruzada que avisar.
+func (tc *Toolchain) dondeCorre(lenguajeBase, plataforma string) string {
+	if tc.estado.es(plataforma, lenguajeBase) {
+		return ""
+	}
+	for _, p := range tc.plataformas {
+		if tc.estado.es(p, lenguajeBase) {
+			return p
+		}
+	}
+	return ""
+}
+
+// OrdenRutas indica cómo se ordenan las rutas al enumerarlas
+type OrdenRutas int
+
+const (
+	PorLargo OrdenRutas = iota // Menos pasos primero; empates por costo
+	PorCosto                   // Menor costo primero; empates por largo
+)
+
+// OpcionesRutas limita la enumeración de rutas. Un límite en 0 significa sin límite.
+type OpcionesRutas struct {
+	MaxRutas       int        // Cuántas rutas devolver como máximo, ya ordenadas
+	MaxProfundidad int        // Cuántas herramientas puede usar una ruta como máximo
+	Orden          OrdenRutas // Criterio para ordenar las rutas
+	Plataforma     string     // Plataforma a la que llegar; vacía significa LOCAL
+}
+
+// TodasLasRutas enumera las rutas simples (sin repetir lenguajes) del lenguaje dado hasta la
+// plataforma de las opciones.
+// Si hay varias herramientas entre el mismo par de lenguajes se cuenta una sola ruta con la más
+// barata, así dos rutas distintas siempre pasan por lenguajes distintos.
+// La búsqueda es primero el mejor: como los costos no son negativos, alargar una ruta nunca la
+// adelanta en el orden, así que las rutas completas salen ya ordenadas y se deja de buscar al
+// llegar a MaxRutas.
+func (tc *Toolchain) TodasLasRutas(lenguaje string, opciones OpcionesRutas) []Ruta {
+	lenguaje = tc.NombreLenguaje(lenguaje)
+	plataforma := tc.NombreLenguaje(cmp.Or(opciones.Plataforma, LENGUAJE_LOCAL))
+	aristas := make(map[string][]arista)
+	salidasDe := func(l string) []arista {
+		if _, ok := aristas[l]; !ok {
+			aristas[l] = masBaratas(tc.aristasDesde(l, plataforma))
+		}
+		return aristas[l]
+	}
+
+	var rutas []Ruta
+	cola := &colaRutas{comparar: compararRutas(opciones.Orden)}
+	heap.Push(cola, Ruta{Lenguajes: []string{lenguaje}})
+	for cola.Len() > 0 && (opciones.MaxRutas == 0 || len(rutas) < opciones.MaxRutas) {
+		ruta := heap.Pop(cola).(Ruta)
+		actual := ruta.Lenguajes[len(ruta.Lenguajes)-1]
+		if actual == plataforma {
+			rutas = append(rutas, ruta)
+			continue
+		}
+		if opciones.MaxProfundidad > 0 && len(ruta.Herramientas) >= opciones.MaxProfundidad {
+			continue
+		}
+		for _, a := range salidasDe(actual) {
+			if slices.Contains(ruta.Lenguajes, a.destino) {
+				continue
+			}
+			heap.Push(cola, Ruta{
+				Lenguajes:    append(slices.Clip(ruta.Lenguajes), a.destino),
+				Herramientas: append(slices.Clip(ruta.Herramientas), a.descripcion()),
+				CorreEn:      append(slices.Clip(ruta.CorreEn), a.corre),
+				Costo:        ruta.Costo + a.costo,
+			})
+		}
+	}
+	return rutas
+}
+
+// colaRutas es la cola de rutas a medio armar de TodasLasRutas, ordenada con comparar
+type colaRutas struct {
+	rutas    []Ruta
+	comparar func(a, b Ruta) int
+}
+
+func (c colaRutas) Len() int           { return len(c.rutas) }
+func (c colaRutas) Less(i, j int) bool { return c.comparar(c.rutas[i], c.rutas[j]) < 0 }
+func (c colaRutas) Swap(i, j int)      { c.rutas[i], c.rutas[j] = c.rutas[j], c.rutas[i] }
+func (c *colaRutas) Push(x any)        { c.rutas = append(c.rutas, x.(Ruta)) }
+func (c *colaRutas) Pop() any {
+	ultima := c.rutas[len(c.rutas)-1]
+	c.rutas = c.rutas[:len(c.rutas)-1]
+	return ultima
+}
+
+// masBaratas deja una sola arista por destino, la de menor costo, conservando el orden
+func masBaratas(salidas []arista) []arista {
+	var resultado []arista
+	indice := make(map[string]int)
+	for _, a := range salidas {
+		if i, ok := indice[a.destino]; ok {
+			if a.costo < resultado[i].costo {
+				resultado[i] = a
+			}
+			continue
+		}
+		indice[a.destino] = len(resultado)
+		resultado = append(resultado, a)
+	}
+	return resultado
+}
+
+// compararRutas compara según el criterio y deja los empates en orden alfabético de lenguajes
+func compararRutas(orden OrdenRutas) func(a, b Ruta) int {
+	return func(a, b Ruta) int {
+		primero := cmp.Compare(len(a.Lenguajes), len(b.Lenguajes))
+		segundo := cmp.Compare(a.Costo, b.Costo)
+		if orden == PorCosto {
+			primero, segundo = segundo, primero
+		}
+		if primero != 0 {
+			return primero
+		}
+		if segundo != 0 {
+			return segundo
+		}
+		return slices.Compare(a.Lenguajes, b.Lenguajes)
+	}
+}
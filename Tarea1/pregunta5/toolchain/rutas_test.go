@@ -0,0 +1,144 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Prueba que BFS encuentra la ruta con menos pasos aunque se haya definido después
+func TestRutaMasCorta(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("C", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("B", "C")
+	tc.DefinirInterprete("A", "B")
+	tc.DefinirInterprete("A", LENGUAJE_LOCAL)
+
+	ruta, ok := tc.RutaMasCorta("A")
+	if !ok || strings.Join(ruta.Lenguajes, " -> ") != "A -> LOCAL" || ruta.Costo != 1 {
+		t.Errorf("Ruta más corta incorrecta: %+v", ruta)
+	}
+
+	// El resultado es siempre el mismo
+	for i := 0; i < 20; i++ {
+		otra, _ := tc.RutaMasCorta("A")
+		if strings.Join(otra.Lenguajes, ",") != strings.Join(ruta.Lenguajes, ",") {
+			t.Fatalf("La ruta cambió entre consultas: %v", otra.Lenguajes)
+		}
+	}
+
+	if _, ok := tc.RutaMasCorta("Z"); ok {
+		t.Errorf("No debería haber ruta para un lenguaje desconocido")
+	}
+}
+
+// Prueba que Dijkstra prefiere la ruta más barata aunque sea más larga
+func TestRutaMasBarata(t *testing.T) {
+	tc := New()
+	tc.DefinirInterpreteConCosto("PY", LENGUAJE_LOCAL, 50)
+	tc.DefinirInterpreteConCosto("C", LENGUAJE_LOCAL, 1)
+	tc.DefinirTraductorConCosto("C", "PY", "C", 5)
+
+	ruta, ok := tc.RutaMasBarata("PY")
+	if !ok || strings.Join(ruta.Lenguajes, " -> ") != "PY -> C -> LOCAL" || ruta.Costo != 6 {
+		t.Errorf("Ruta más barata incorrecta: %+v", ruta)
+	}
+
+	corta, _ := tc.RutaMasCorta("PY")
+	if strings.Join(corta.Lenguajes, " -> ") != "PY -> LOCAL" || corta.Costo != 50 {
+		t.Errorf("Ruta más corta incorrecta: %+v", corta)
+	}
+
+	// El traductor escrito en un lenguaje no ejecutable no entra en la ruta
+	tc2 := New()
+	tc2.DefinirInterpreteConCosto("PY", LENGUAJE_LOCAL, 50)
+	tc2.DefinirInterpreteConCosto("C", LENGUAJE_LOCAL, 1)
+	tc2.DefinirTraductorConCosto("RUST", "PY", "C", 0)
+	ruta, _ = tc2.RutaMasBarata("PY")
+	if strings.Join(ruta.Lenguajes, " -> ") != "PY -> LOCAL" {
+		t.Errorf("Se usó un traductor que no se puede ejecutar: %+v", ruta)
+	}
+
+	if _, ok := tc2.RutaMasBarata("Z"); ok {
+		t.Errorf("No debería haber ruta para un lenguaje desconocido")
+	}
+	if err := tc2.DefinirInterpreteConCosto("X", LENGUAJE_LOCAL, -1); err == nil {
+		t.Errorf("Debería rechazar costos negativos")
+	}
+}
+
+// Prueba que se enumeran todas las rutas simples respetando el orden y los límites
+func TestTodasLasRutas(t *testing.T) {
+	tc := New()
+	tc.DefinirInterpreteConCosto("C", LENGUAJE_LOCAL, 1)
+	tc.DefinirInterpreteConCosto("JS", LENGUAJE_LOCAL, 10)
+	tc.DefinirInterpreteConCosto("TS", LENGUAJE_LOCAL, 30)
+	tc.DefinirTraductorConCosto("C", "TS", "JS", 2)
+	tc.DefinirTraductorConCosto("C", "TS", "C", 20)
+	tc.DefinirTraductorConCosto("C", "JS", "C", 3)
+	// Un segundo intérprete de TS más barato no agrega rutas, solo abarata la existente
+	tc.DefinirInterpreteConCosto("TS", "C", 25)
+	tc.DefinirInterpreteConCosto("JS", "C", 4)
+
+	texto := func(rutas []Ruta) []string {
+		var lista []string
+		for _, r := range rutas {
+			lista = append(lista, strings.Join(r.Lenguajes, ">"))
+		}
+		return lista
+	}
+
+	porLargo := tc.TodasLasRutas("TS", OpcionesRutas{Orden: PorLargo})
+	esperado := []string{"TS>LOCAL", "TS>JS>LOCAL", "TS>C>LOCAL", "TS>JS>C>LOCAL"}
+	if strings.Join(texto(porLargo), " ") != strings.Join(esperado, " ") {
+		t.Errorf("Orden por largo incorrecto: %v", texto(porLargo))
+	}
+
+	porCosto := tc.TodasLasRutas("TS", OpcionesRutas{Orden: PorCosto})
+	esperado = []string{"TS>JS>C>LOCAL", "TS>JS>LOCAL", "TS>C>LOCAL", "TS>LOCAL"}
+	if strings.Join(texto(porCosto), " ") != strings.Join(esperado, " ") {
+		t.Errorf("Orden por costo incorrecto: %v", texto(porCosto))
+	}
+	if porCosto[0].Costo != 6 || porCosto[2].Costo != 21 {
+		t.Errorf("Costos incorrectos: %v", porCosto)
+	}
+
+	limitadas := tc.TodasLasRutas("TS", OpcionesRutas{Orden: PorCosto, MaxRutas: 2, MaxProfundidad: 2})
+	esperado = []string{"TS>JS>LOCAL", "TS>C>LOCAL"}
+	if strings.Join(texto(limitadas), " ") != strings.Join(esperado, " ") {
+		t.Errorf("Límites mal aplicados: %v", texto(limitadas))
+	}
+
+	// Con MaxRutas se deja de buscar antes, pero las primeras rutas son las mismas
+	for _, orden := range []OrdenRutas{PorLargo, PorCosto} {
+		todas := texto(tc.TodasLasRutas("TS", OpcionesRutas{Orden: orden}))
+		primeras := texto(tc.TodasLasRutas("TS", OpcionesRutas{Orden: orden, MaxRutas: 3}))
+		if !slices.Equal(primeras, todas[:3]) {
+			t.Errorf("MaxRutas cambió las primeras rutas: %v en vez de %v", primeras, todas[:3])
+		}
+	}
+
+	if rutas := tc.TodasLasRutas("Z", OpcionesRutas{}); len(rutas) != 0 {
+		t.Errorf("No debería haber rutas para un lenguaje desconocido: %v", rutas)
+	}
+}
+
+// Prueba que MaxRutas corta la búsqueda en un grafo con demasiadas rutas para enumerarlas todas
+func TestTodasLasRutasCortaPronto(t *testing.T) {
+	tc := New()
+	lenguajes := []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N"}
+	for _, origen := range lenguajes {
+		tc.DefinirInterpreteConCosto(origen, LENGUAJE_LOCAL, 100)
+		for _, destino := range lenguajes {
+			if origen != destino {
+				tc.DefinirInterpreteConCosto(origen, destino, 1)
+			}
+		}
+	}
+
+	rutas := tc.TodasLasRutas("A", OpcionesRutas{Orden: PorLargo, MaxRutas: 3})
+	if len(rutas) != 3 || len(rutas[0].Lenguajes) != 2 || len(rutas[1].Lenguajes) != 3 {
+		t.Errorf("Rutas incorrectas: %+v", rutas)
+	}
+}
@@ -0,0 +1,462 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// El lenguaje LOCAL representa el que la compu puede ejecutar directamente. Es la plataforma
+// que tiene todo catálogo; se pueden declarar otras con DefinirPlataforma.
+const LENGUAJE_LOCAL = "LOCAL"
+
+// errorRepetido es el error de definir algo que ya estaba en el catálogo
+type errorRepetido struct {
+	mensaje string
+}
+
+func (e errorRepetido) Error() string {
+	return e.mensaje
+}
+
+// EsRepetido indica si el error vino de definir algo que ya estaba en el catálogo, para
+// distinguirlo de una definición inválida
+func EsRepetido(err error) bool {
+	return errors.As(err, new(errorRepetido))
+}
+
+// errorNoDefinido es el error de usar o eliminar algo que no está en el catálogo
+type errorNoDefinido struct {
+	mensaje string
+}
+
+func (e errorNoDefinido) Error() string {
+	return e.mensaje
+}
+
+// noDefinido arma el error de algo que no está en el catálogo
+func noDefinido(formato string, args ...any) error {
+	return errorNoDefinido{fmt.Sprintf(formato, args...)}
+}
+
+// EsNoDefinido indica si el error vino de buscar algo que no estaba en el catálogo, para
+// distinguirlo de un nombre o una versión mal escritos
+func EsNoDefinido(err error) bool {
+	return errors.As(err, new(errorNoDefinido))
+}
+
+// Estructura para guardar la info de un programa y su lenguaje. El lenguaje puede llevar una
+// versión concreta, como "Python@3.11".
+type Programa struct {
+	Nombre   string
+	Lenguaje string
+	Codigo   string // Código fuente opcional, para correrlo con Ejecutar
+}
+
+// Estructura para guardar la info de un intérprete (qué lenguaje interpreta y en cuál está hecho).
+// Interpreta las versiones de LenguajeBase que caen en Versiones y está hecho en un lenguaje
+// concreto, que puede llevar versión.
+type Interprete struct {
+	LenguajeBase string
+	Versiones    Rango
+	Lenguaje     string
+	Costo        float64 // Costo de usarlo en una ruta, por ejemplo cuánto hace más lento al programa
+}
+
+// Estructura para guardar la info de un traductor (en qué lenguaje está hecho, de cuál a cuál traduce).
+// Traduce las versiones de LenguajeOrigen que caen en VersionesOrigen; el lenguaje en que está
+// hecho y el que produce son concretos y pueden llevar versión.
+type Traductor struct {
+	LenguajeBase    string
+	LenguajeOrigen  string
+	VersionesOrigen Rango
+	LenguajeDestino string
+	Costo           float64 // Costo de usarlo en una ruta, por ejemplo el tiempo de compilación
+}
+
+// Costo que tienen las herramientas definidas sin costo explícito
+const COSTO_POR_DEFECTO = 1.0
+
+// Toolchain guarda un catálogo de programas, intérpretes y traductores. Los programas se
+// guardan por nombre; los intérpretes y traductores forman un multigrafo entre lenguajes,
+// así que se guardan todos aunque haya varios para el mismo lenguaje.
+//
+// Los nombres de lenguaje pasan por la política de normalización y los alias antes de
+// guardarse o buscarse, así "python", "Python" y "py" pueden ser el mismo lenguaje.
+//
+// Las plataformas son lenguajes que una máquina ejecuta directamente, como LOCAL, X86 o ARM.
+// El conjunto de lenguajes ejecutables en cada plataforma se mantiene al día con cada
+// definición, así que preguntar si un lenguaje es ejecutable no recorre el catálogo. Los
+// índices por lenguaje guardan las herramientas en el orden en que se definieron.
+type Toolchain struct {
+	programas   map[string]Programa
+	interpretes []Interprete
+	traductores []Traductor
+	plataformas []string            // LOCAL primero y después en el orden en que se declararon
+	nodosDe     map[string][]string // Por nombre, los lenguajes concretos que aparecen en las definiciones
+
+	normalizacion Normalizacion
+	nombres       map[string]string // Por clave normalizada, cómo se escribe el lenguaje
+	definidos     map[string]bool   // Claves de los lenguajes cuya forma ya fijó una definición
+	alias         map[string]Alias  // Por clave normalizada del alias
+
+	estado            *ejecutabilidad
+	interpretesDe     map[string][]Interprete // Por lenguaje interpretado
+	interpretesEn     map[string][]Interprete // Por lenguaje en que está escrito
+	traductoresDesde  map[string][]Traductor  // Por lenguaje de origen
+	traductoresSegunL map[string][]Traductor  // Por lenguaje base y por lenguaje destino
+
+	implInterpretes map[[2]string]Implementacion // Por lenguaje interpretado y lenguaje en que está escrito
+	implTraductores map[[3]string]Implementacion // Por lenguaje base, de origen y de destino
+}
+
+// New crea un catálogo vacío
+func New() *Toolchain {
+	tc := &Toolchain{
+		programas:         make(map[string]Programa),
+		plataformas:       []string{LENGUAJE_LOCAL},
+		nodosDe:           map[string][]string{LENGUAJE_LOCAL: {LENGUAJE_LOCAL}},
+		nombres:           map[string]string{IgnorarMayusculas.clave(LENGUAJE_LOCAL): LENGUAJE_LOCAL},
+		definidos:         map[string]bool{IgnorarMayusculas.clave(LENGUAJE_LOCAL): true},
+		alias:             make(map[string]Alias),
+		interpretesDe:     make(map[string][]Interprete),
+		interpretesEn:     make(map[string][]Interprete),
+		traductoresDesde:  make(map[string][]Traductor),
+		traductoresSegunL: make(map[string][]Traductor),
+		implInterpretes:   make(map[[2]string]Implementacion),
+		implTraductores:   make(map[[3]string]Implementacion),
+	}
+	tc.recalcularEjecutables()
+	return tc
+}
+
+// DefinirPlataforma declara otra plataforma nativa. Su lenguaje se ejecuta en ella directamente.
+func (tc *Toolchain) DefinirPlataforma(escrito string) error {
+	nombre, err := tc.leerNodo(escrito)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(tc.plataformas, nombre) {
+		return errorRepetido{fmt.Sprintf("la plataforma %s ya estaba definida", nombre)}
+	}
+	tc.definirLenguaje(escrito)
+	nombre = tc.NombreLenguaje(nombre)
+	tc.registrar(nombre)
+	tc.plataformas = append(tc.plataformas, nombre)
+	tc.estado.en[nombre] = make(map[string]bool)
+	tc.marcar(tc.estado, nombre, nombre)
+	return nil
+}
+
+// Plataformas devuelve las plataformas, con LOCAL primero
+func (tc *Toolchain) Plataformas() []string {
+	return slices.Clone(tc.plataformas)
+}
+
+// DefinirPrograma guarda un programa; si ya había uno con ese nombre lo reemplaza. Devuelve
+// error si el lenguaje no es concreto, por ejemplo si tiene un rango de versiones.
+func (tc *Toolchain) DefinirPrograma(nombre, lenguaje string) error {
+	lenguaje, err := tc.leerNodo(lenguaje)
+	if err != nil {
+		return err
+	}
+	tc.registrar(lenguaje)
+	tc.programas[nombre] = Programa{Nombre: nombre, Lenguaje: lenguaje}
+	return nil
+}
+
+// DefinirInterprete guarda un intérprete de lenguajeBase escrito en lenguaje, con el costo por defecto.
+// Devuelve error si ese mismo intérprete ya estaba definido.
+func (tc *Toolchain) DefinirInterprete(lenguajeBase, lenguaje string) error {
+	return tc.DefinirInterpreteConCosto(lenguajeBase, lenguaje, COSTO_POR_DEFECTO)
+}
+
+// DefinirInterpreteConCosto guarda un intérprete con el costo dado. El lenguaje interpretado
+// puede llevar un rango de versiones, como "Python>=3.8", y el lenguaje en que está hecho una
+// versión concreta, como "C@11".
+func (tc *Toolchain) DefinirInterpreteConCosto(lenguajeBase, lenguaje string, costo float64) error {
+	if costo < 0 {
+		return fmt.Errorf("el costo no puede ser negativo")
+	}
+	base, versiones, err := tc.leerEntrada(lenguajeBase)
+	if err != nil {
+		return err
+	}
+	lenguaje, err = tc.leerNodo(lenguaje)
+	if err != nil {
+		return err
+	}
+	interp := Interprete{LenguajeBase: base, Versiones: versiones, Lenguaje: lenguaje, Costo: costo}
+	for _, otro := range tc.interpretesDe[base] {
+		if otro.Versiones.igual(versiones) && otro.Lenguaje == lenguaje {
+			return errorRepetido{fmt.Sprintf("el %s ya estaba definido", interp)}
+		}
+	}
+	tc.definirLenguaje(lenguajeBase)
+	base, lenguaje = tc.NombreLenguaje(base), tc.NombreLenguaje(lenguaje)
+	interp.LenguajeBase, interp.Lenguaje = base, lenguaje
+	tc.registrar(lenguaje)
+	if versiones == (Rango{}) {
+		tc.registrar(base)
+	} else {
+		tc.registrarLenguaje(base)
+	}
+	tc.interpretes = append(tc.interpretes, interp)
+	tc.indexarInterprete(interp)
+
+	for _, plataforma := range tc.plataformas {
+		if interpreteUsable(tc.estado, plataforma, interp) {
+			for _, nodo := range tc.nodosCompatibles(base, versiones) {
+				tc.marcar(tc.estado, plataforma, nodo)
+			}
+		}
+	}
+	return nil
+}
+
+// DefinirTraductor guarda un traductor escrito en lenguajeBase que traduce de origen a destino,
+// con el costo por defecto. Devuelve error si ese mismo traductor ya estaba definido.
+func (tc *Toolchain) DefinirTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino string) error {
+	return tc.DefinirTraductorConCosto(lenguajeBase, lenguajeOrigen, lenguajeDestino, COSTO_POR_DEFECTO)
+}
+
+// DefinirTraductorConCosto guarda un traductor con el costo dado
+func (tc *Toolchain) DefinirTraductorConCosto(lenguajeBase, lenguajeOrigen, lenguajeDestino string, costo float64) error {
+	if costo < 0 {
+		return fmt.Errorf("el costo no puede ser negativo")
+	}
+	lenguajeBase, err := tc.leerNodo(lenguajeBase)
+	if err != nil {
+		return err
+	}
+	origen, versiones, err := tc.leerEntrada(lenguajeOrigen)
+	if err != nil {
+		return err
+	}
+	lenguajeDestino, err = tc.leerNodo(lenguajeDestino)
+	if err != nil {
+		return err
+	}
+	trad := Traductor{
+		LenguajeBase:    lenguajeBase,
+		LenguajeOrigen:  origen,
+		VersionesOrigen: versiones,
+		LenguajeDestino: lenguajeDestino,
+		Costo:           costo,
+	}
+	for _, otro := range tc.traductoresDesde[origen] {
+		if otro.LenguajeBase == lenguajeBase && otro.VersionesOrigen.igual(versiones) && otro.LenguajeDestino == lenguajeDestino {
+			return errorRepetido{fmt.Sprintf("el %s ya estaba definido", trad)}
+		}
+	}
+	tc.definirLenguaje(lenguajeOrigen)
+	origen, lenguajeBase, lenguajeDestino = tc.NombreLenguaje(origen), tc.NombreLenguaje(lenguajeBase), tc.NombreLenguaje(lenguajeDestino)
+	trad.LenguajeOrigen, trad.LenguajeBase, trad.LenguajeDestino = origen, lenguajeBase, lenguajeDestino
+	tc.registrar(lenguajeBase, lenguajeDestino)
+	if versiones == (Rango{}) {
+		tc.registrar(origen)
+	} else {
+		tc.registrarLenguaje(origen)
+	}
+	tc.traductores = append(tc.traductores, trad)
+	tc.indexarTraductor(trad)
+
+	for _, plataforma := range tc.plataformas {
+		if traductorUsable(tc.estado, plataforma, trad) {
+			for _, nodo := range tc.nodosCompatibles(origen, versiones) {
+				tc.marcar(tc.estado, plataforma, nodo)
+			}
+		}
+	}
+	return nil
+}
+
+// indexarInterprete agrega el intérprete a los índices por lenguaje
+func (tc *Toolchain) indexarInterprete(interp Interprete) {
+	tc.interpretesDe[interp.LenguajeBase] = append(tc.interpretesDe[interp.LenguajeBase], interp)
+	tc.interpretesEn[interp.Lenguaje] = append(tc.interpretesEn[interp.Lenguaje], interp)
+}
+
+// indexarTraductor agrega el traductor a los índices por lenguaje
+func (tc *Toolchain) indexarTraductor(trad Traductor) {
+	tc.traductoresDesde[trad.LenguajeOrigen] = append(tc.traductoresDesde[trad.LenguajeOrigen], trad)
+	tc.traductoresSegunL[trad.LenguajeBase] = append(tc.traductoresSegunL[trad.LenguajeBase], trad)
+	if trad.LenguajeDestino != trad.LenguajeBase {
+		tc.traductoresSegunL[trad.LenguajeDestino] = append(tc.traductoresSegunL[trad.LenguajeDestino], trad)
+	}
+}
+
+// Programa busca un programa por nombre
+func (tc *Toolchain) Programa(nombre string) (Programa, bool) {
+	prog, ok := tc.programas[nombre]
+	return prog, ok
+}
+
+// Programas devuelve los programas ordenados por nombre
+func (tc *Toolchain) Programas() []Programa {
+	lista := make([]Programa, 0, len(tc.programas))
+	for _, prog := range tc.programas {
+		lista = append(lista, prog)
+	}
+	sort.Slice(lista, func(i, j int) bool { return lista[i].Nombre < lista[j].Nombre })
+	return lista
+}
+
+// buscarInterprete encuentra un intérprete definido a partir de cómo se escribió al definirlo.
+// Si la versión está mal escrita devuelve ese error en vez de decir que no está definido.
+func (tc *Toolchain) buscarInterprete(lenguajeBase, lenguaje string) (Interprete, error) {
+	base, versiones, err := tc.leerEntrada(lenguajeBase)
+	if err != nil {
+		return Interprete{}, err
+	}
+	nombre := tc.NombreLenguaje(lenguaje)
+	for _, interp := range tc.interpretesDe[base] {
+		if interp.Versiones.igual(versiones) && interp.Lenguaje == nombre {
+			return interp, nil
+		}
+	}
+	return Interprete{}, noDefinido("el intérprete de %s en %s no estaba definido", lenguajeBase, lenguaje)
+}
+
+// buscarTraductor encuentra un traductor definido a partir de cómo se escribió al definirlo.
+// Si la versión está mal escrita devuelve ese error en vez de decir que no está definido.
+func (tc *Toolchain) buscarTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino string) (Traductor, error) {
+	origen, versiones, err := tc.leerEntrada(lenguajeOrigen)
+	if err != nil {
+		return Traductor{}, err
+	}
+	base, destino := tc.NombreLenguaje(lenguajeBase), tc.NombreLenguaje(lenguajeDestino)
+	for _, trad := range tc.traductoresDesde[origen] {
+		if trad.LenguajeBase == base && trad.VersionesOrigen.igual(versiones) && trad.LenguajeDestino == destino {
+			return trad, nil
+		}
+	}
+	return Traductor{}, noDefinido("el traductor de %s de %s a %s no estaba definido", lenguajeBase, lenguajeOrigen, lenguajeDestino)
+}
+
+// Interpretes devuelve una copia de los intérpretes en el orden en que se definieron
+func (tc *Toolchain) Interpretes() []Interprete {
+	return slices.Clone(tc.interpretes)
+}
+
+// Traductores devuelve una copia de los traductores en el orden en que se definieron
+func (tc *Toolchain) Traductores() []Traductor {
+	return slices.Clone(tc.traductores)
+}
+
+// EsEjecutable indica si el lenguaje se puede ejecutar en LOCAL, directamente o con herramientas
+func (tc *Toolchain) EsEjecutable(lenguaje string) bool {
+	return tc.EsEjecutableEn(lenguaje, LENGUAJE_LOCAL)
+}
+
+// EsEjecutableEn indica si el lenguaje se puede ejecutar en la plataforma dada
+func (tc *Toolchain) EsEjecutableEn(lenguaje, plataforma string) bool {
+	lenguaje, plataforma = tc.NombreLenguaje(lenguaje), tc.NombreLenguaje(plataforma)
+	return tc.ejecutableEn(tc.estado, plataforma, lenguaje)
+}
+
+// ejecutabilidad guarda qué lenguajes se pueden ejecutar en cada plataforma
+type ejecutabilidad struct {
+	en       map[string]map[string]bool // Por plataforma, los lenguajes que se ejecutan en ella
+	enAlguna map[string]bool            // Lenguajes que se ejecutan en al menos una plataforma
+}
+
+// es indica si el lenguaje se ejecuta en la plataforma
+func (e *ejecutabilidad) es(plataforma, lenguaje string) bool {
+	return e.en[plataforma][lenguaje]
+}
+
+// clonar copia el estado para probar definiciones sin tocar el catálogo
+func (e *ejecutabilidad) clonar() *ejecutabilidad {
+	copia := &ejecutabilidad{en: make(map[string]map[string]bool), enAlguna: maps.Clone(e.enAlguna)}
+	for plataforma, lenguajes := range e.en {
+		copia.en[plataforma] = maps.Clone(lenguajes)
+	}
+	return copia
+}
+
+// interpreteUsable indica si el intérprete corre en la plataforma, es decir, si su lenguaje se
+// ejecuta en ella
+func interpreteUsable(e *ejecutabilidad, plataforma string, interp Interprete) bool {
+	return e.es(plataforma, interp.Lenguaje)
+}
+
+// traductorUsable indica si el traductor sirve para llegar a la plataforma: tiene que correr en
+// alguna plataforma, no necesariamente la misma, y su salida tiene que ejecutarse en el destino
+func traductorUsable(e *ejecutabilidad, plataforma string, trad Traductor) bool {
+	return e.enAlguna[trad.LenguajeBase] && e.es(plataforma, trad.LenguajeDestino)
+}
+
+// InterpreteUsable indica si el intérprete se puede usar en al menos una plataforma
+func (tc *Toolchain) InterpreteUsable(interp Interprete) bool {
+	return slices.ContainsFunc(tc.plataformas, func(p string) bool { return interpreteUsable(tc.estado, p, interp) })
+}
+
+// TraductorUsable indica si el traductor sirve para llegar a al menos una plataforma
+func (tc *Toolchain) TraductorUsable(trad Traductor) bool {
+	return slices.ContainsFunc(tc.plataformas, func(p string) bool { return traductorUsable(tc.estado, p, trad) })
+}
+
+// Marca un lenguaje como ejecutable en una plataforma y propaga por los índices, con la
+// semántica de los diagramas T: un intérprete de L escrito en M sirve en P si M se ejecuta en P,
+// y un traductor de L a M escrito en B sirve para P si B corre en alguna plataforma y M se
+// ejecuta en P (compilación cruzada). Cada par plataforma y lenguaje entra una sola vez a la
+// cola, así que el costo es proporcional a las herramientas que lo tocan.
+func (tc *Toolchain) marcar(e *ejecutabilidad, plataforma, lenguaje string) {
+	type par struct{ plataforma, lenguaje string }
+	var cola []par
+	agregar := func(p, l string) {
+		if !e.en[p][l] {
+			e.en[p][l] = true
+			cola = append(cola, par{p, l})
+		}
+	}
+
+	agregar(plataforma, lenguaje)
+	for len(cola) > 0 {
+		actual := cola[len(cola)-1]
+		cola = cola[:len(cola)-1]
+		nuevoEnAlguna := !e.enAlguna[actual.lenguaje]
+		e.enAlguna[actual.lenguaje] = true
+
+		for _, interp := range tc.interpretesEn[actual.lenguaje] {
+			for _, nodo := range tc.nodosCompatibles(interp.LenguajeBase, interp.Versiones) {
+				agregar(actual.plataforma, nodo)
+			}
+		}
+		for _, trad := range tc.traductoresSegunL[actual.lenguaje] {
+			if trad.LenguajeDestino == actual.lenguaje && e.enAlguna[trad.LenguajeBase] {
+				for _, nodo := range tc.nodosCompatibles(trad.LenguajeOrigen, trad.VersionesOrigen) {
+					agregar(actual.plataforma, nodo)
+				}
+			}
+			// El traductor recién empezó a correr en alguna parte: sirve en toda plataforma
+			// donde ya se ejecuta su salida
+			if trad.LenguajeBase == actual.lenguaje && nuevoEnAlguna {
+				for _, p := range tc.plataformas {
+					if e.en[p][trad.LenguajeDestino] {
+						for _, nodo := range tc.nodosCompatibles(trad.LenguajeOrigen, trad.VersionesOrigen) {
+							agregar(p, nodo)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// recalcularEjecutables rehace el estado desde las plataformas; se usa cuando se borra una
+// herramienta que se estaba usando, porque no se puede saber sin recorrer si había otra forma
+// de llegar
+func (tc *Toolchain) recalcularEjecutables() {
+	tc.estado = &ejecutabilidad{en: make(map[string]map[string]bool), enAlguna: make(map[string]bool)}
+	for _, plataforma := range tc.plataformas {
+		tc.estado.en[plataforma] = make(map[string]bool)
+	}
+	for _, plataforma := range tc.plataformas {
+		tc.marcar(tc.estado, plataforma, plataforma)
+	}
+}
@@ -0,0 +1,131 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"strings"
+	"testing"
+)
+
+// Prueba para ver si EsEjecutable funciona bien
+func TestEsEjecutable(t *testing.T) {
+	tc := New()
+	if !tc.EsEjecutable(LENGUAJE_LOCAL) {
+		t.Errorf("LOCAL debería ser siempre ejecutable.")
+	}
+	if tc.EsEjecutable("GO") {
+		t.Errorf("GO no debería ser ejecutable sin un intérprete.")
+	}
+	tc.DefinirInterprete("GO", LENGUAJE_LOCAL)
+	if !tc.EsEjecutable("GO") {
+		t.Errorf("GO debería ser ejecutable con un intérprete GO -> LOCAL.")
+	}
+}
+
+// Prueba para checar que RutaEjecucion encuentra la ruta correcta
+func TestRutaEjecucion(t *testing.T) {
+	tc := New()
+
+	path, found := tc.RutaEjecucion(LENGUAJE_LOCAL)
+	if !found || strings.Join(path, " -> ") != "LOCAL" {
+		t.Errorf("Ruta para LOCAL a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+	}
+
+	tc.DefinirInterprete("GO", LENGUAJE_LOCAL)
+	path, found = tc.RutaEjecucion("GO")
+	if !found || strings.Join(path, " -> ") != "GO -> LOCAL" {
+		t.Errorf("Ruta para GO a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+	}
+
+	tc.DefinirInterprete("JS", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("JS", "TS", "JS")
+	path, found = tc.RutaEjecucion("TS")
+	if !found || strings.Join(path, " -> ") != "TS -> JS -> LOCAL" {
+		t.Errorf("Ruta para TS a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+	}
+
+	tc.DefinirInterprete("BINARIO", LENGUAJE_LOCAL)
+	tc.DefinirTraductor("C", "PYTHON", "C")
+	tc.DefinirTraductor("BINARIO", "C", "BINARIO")
+	path, found = tc.RutaEjecucion("PYTHON")
+	if !found || strings.Join(path, " -> ") != "PYTHON -> C -> BINARIO -> LOCAL" {
+		t.Errorf("Ruta para PYTHON a LOCAL incorrecta. Obtenido: %v, %v", path, found)
+	}
+
+	_, found = tc.RutaEjecucion("UNKNOWN_LANG")
+	if found {
+		t.Errorf("Se encontró una ruta para un lenguaje desconocido, lo cual es incorrecto.")
+	}
+}
+
+// Prueba la semántica de diagramas T: una herramienta solo sirve si su lenguaje base es ejecutable
+func TestHerramientasBloqueadas(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("JS", LENGUAJE_LOCAL)
+
+	// Traductor de TS a JS escrito en RUST, que todavía no se puede ejecutar
+	tc.DefinirTraductor("RUST", "TS", "JS")
+	if _, found := tc.RutaEjecucion("TS"); found {
+		t.Errorf("El traductor escrito en RUST no debería servir si RUST no es ejecutable")
+	}
+
+	// Intérprete de LUA alojado en PERL, que tampoco es ejecutable
+	tc.DefinirInterprete("LUA", "PERL")
+	if tc.EsEjecutable("LUA") {
+		t.Errorf("LUA no debería ser ejecutable si su intérprete está escrito en PERL")
+	}
+
+	// Al definir un intérprete de RUST en LUA y uno de PERL en JS todo se vuelve ejecutable
+	tc.DefinirInterprete("RUST", "LUA")
+	tc.DefinirInterprete("PERL", "JS")
+	path, found := tc.RutaEjecucion("TS")
+	if !found || strings.Join(path, " -> ") != "TS -> JS -> LOCAL" {
+		t.Errorf("Ruta para TS a LOCAL incorrecta después del punto fijo. Obtenido: %v, %v", path, found)
+	}
+	if !tc.EsEjecutable("LUA") || !tc.EsEjecutable("RUST") {
+		t.Errorf("LUA y RUST deberían ser ejecutables al resolver el punto fijo")
+	}
+}
+
+// Prueba que se guardan varios intérpretes del mismo lenguaje y que se detectan los duplicados
+func TestVariosInterpretes(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("C", "Python")
+	tc.DefinirInterprete("C", "Java")
+	if err := tc.DefinirInterprete("C", "Java"); err == nil || !strings.Contains(err.Error(), "ya estaba definido") {
+		t.Errorf("No se detectó el intérprete duplicado: %v", err)
+	}
+	tc.DefinirTraductor("C", "Java", "C")
+	if err := tc.DefinirTraductor("C", "Java", "C"); err == nil || !strings.Contains(err.Error(), "ya estaba definido") {
+		t.Errorf("No se detectó el traductor duplicado: %v", err)
+	}
+	if len(tc.Interpretes()) != 2 || len(tc.Traductores()) != 1 {
+		t.Errorf("Se esperaban 2 intérpretes y 1 traductor, hay %d y %d", len(tc.Interpretes()), len(tc.Traductores()))
+	}
+
+	// El intérprete de C en Java sirve aunque el de C en Python no
+	tc.DefinirInterprete("Java", LENGUAJE_LOCAL)
+	if !tc.EsEjecutable("C") {
+		t.Errorf("C debería ser ejecutable con el intérprete escrito en Java")
+	}
+}
+
+// Prueba que varios catálogos pueden convivir sin compartir definiciones
+func TestCatalogosIndependientes(t *testing.T) {
+	a := New()
+	b := New()
+	a.DefinirPrograma("app", "GO")
+	a.DefinirInterprete("GO", LENGUAJE_LOCAL)
+
+	if _, ok := b.Programa("app"); ok {
+		t.Errorf("El programa de un catálogo no debería aparecer en otro")
+	}
+	if b.EsEjecutable("GO") {
+		t.Errorf("El intérprete de un catálogo no debería servir en otro")
+	}
+
+	a.DefinirPrograma("otra", "C")
+	programas := a.Programas()
+	if len(programas) != 2 || programas[0].Nombre != "app" || programas[1].Nombre != "otra" {
+		t.Errorf("Programas no devolvió la lista ordenada: %v", programas)
+	}
+}
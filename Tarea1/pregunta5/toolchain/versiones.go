@@ -0,0 +1,346 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Los lenguajes pueden llevar versión. Donde una herramienta corre o lo que produce es una
+// versión concreta, escrita "Python@3.11"; lo que una herramienta acepta es un rango, escrito
+// "Python>=3.8", "Python>=3.8,<4" o "Python@3.11" para una sola versión. Un lenguaje sin
+// versión acepta todas, así que un catálogo sin versiones funciona como siempre.
+
+// Rango es un intervalo de versiones; el valor cero acepta cualquier versión
+type Rango struct {
+	Min, Max               string // Versiones límite; vacía significa sin límite
+	IncluyeMin, IncluyeMax bool
+}
+
+// Contiene indica si la versión está en el rango. Un lenguaje sin versión solo está en el
+// rango que acepta todo, porque no se sabe qué versión es.
+func (r Rango) Contiene(version string) bool {
+	if r == (Rango{}) {
+		return true
+	}
+	if version == "" {
+		return false
+	}
+	if r.Min != "" {
+		if c := compararVersiones(version, r.Min); c < 0 || (c == 0 && !r.IncluyeMin) {
+			return false
+		}
+	}
+	if r.Max != "" {
+		if c := compararVersiones(version, r.Max); c > 0 || (c == 0 && !r.IncluyeMax) {
+			return false
+		}
+	}
+	return true
+}
+
+// igual indica si los dos rangos aceptan las mismas versiones, aunque se escriban distinto
+// (por ejemplo >=3.8 y >=3.8.0)
+func (r Rango) igual(otro Rango) bool {
+	return (r.Min == "") == (otro.Min == "") && (r.Max == "") == (otro.Max == "") &&
+		(r.Min == "" || compararVersiones(r.Min, otro.Min) == 0 && r.IncluyeMin == otro.IncluyeMin) &&
+		(r.Max == "" || compararVersiones(r.Max, otro.Max) == 0 && r.IncluyeMax == otro.IncluyeMax)
+}
+
+// vacio indica si ninguna versión cumple el rango
+func (r Rango) vacio() bool {
+	if r.Min == "" || r.Max == "" {
+		return false
+	}
+	c := compararVersiones(r.Min, r.Max)
+	return c > 0 || (c == 0 && !(r.IncluyeMin && r.IncluyeMax))
+}
+
+// String escribe el rango como se pone después del nombre del lenguaje
+func (r Rango) String() string {
+	if r.Min != "" && r.Max != "" && compararVersiones(r.Min, r.Max) == 0 {
+		return "@" + r.Min
+	}
+	var partes []string
+	if r.Min != "" && r.IncluyeMin {
+		partes = append(partes, ">="+r.Min)
+	} else if r.Min != "" {
+		partes = append(partes, ">"+r.Min)
+	}
+	if r.Max != "" && r.IncluyeMax {
+		partes = append(partes, "<="+r.Max)
+	} else if r.Max != "" {
+		partes = append(partes, "<"+r.Max)
+	}
+	return strings.Join(partes, ",")
+}
+
+// leerRango interpreta lo que va después del nombre: "", "@3.11" o condiciones separadas por
+// comas con los operadores >=, >, <=, < y ==
+func leerRango(texto string) (Rango, error) {
+	var r Rango
+	if texto == "" {
+		return r, nil
+	}
+	if version, ok := strings.CutPrefix(texto, "@"); ok {
+		texto = "==" + version
+	}
+	for _, condicion := range strings.Split(texto, ",") {
+		operador := strings.TrimRight(condicion, "0123456789.")
+		version := condicion[len(operador):]
+		if !versionValida(version) {
+			return Rango{}, fmt.Errorf("versión inválida en '%s'", condicion)
+		}
+		switch operador {
+		case ">=", ">":
+			r.acotarAbajo(version, operador == ">=")
+		case "<=", "<":
+			r.acotarArriba(version, operador == "<=")
+		case "==":
+			r.acotarAbajo(version, true)
+			r.acotarArriba(version, true)
+		default:
+			return Rango{}, fmt.Errorf("operador de versión '%s' desconocido", operador)
+		}
+	}
+	if r.vacio() {
+		return Rango{}, fmt.Errorf("ninguna versión cumple '%s'", texto)
+	}
+	return r, nil
+}
+
+// acotarAbajo se queda con el límite inferior más alto entre el actual y el dado
+func (r *Rango) acotarAbajo(version string, incluye bool) {
+	c := compararVersiones(version, r.Min)
+	if r.Min == "" || c > 0 {
+		r.Min, r.IncluyeMin = version, incluye
+	} else if c == 0 {
+		r.IncluyeMin = r.IncluyeMin && incluye
+	}
+}
+
+// acotarArriba se queda con el límite superior más bajo entre el actual y el dado
+func (r *Rango) acotarArriba(version string, incluye bool) {
+	c := compararVersiones(version, r.Max)
+	if r.Max == "" || c < 0 {
+		r.Max, r.IncluyeMax = version, incluye
+	} else if c == 0 {
+		r.IncluyeMax = r.IncluyeMax && incluye
+	}
+}
+
+// versionValida acepta números separados por puntos, como 3, 3.11 o 2.7.18
+func versionValida(version string) bool {
+	if version == "" {
+		return false
+	}
+	for _, parte := range strings.Split(version, ".") {
+		if _, err := strconv.Atoi(parte); err != nil || strings.HasPrefix(parte, "-") || strings.HasPrefix(parte, "+") {
+			return false
+		}
+	}
+	return true
+}
+
+// compararVersiones compara parte por parte; las partes que faltan valen 0, así 3.8 == 3.8.0
+func compararVersiones(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if c := cmp.Compare(na, nb); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// separarNombre divide "Python>=3.8" en el nombre y lo que sigue
+func separarNombre(espec string) (string, string) {
+	if i := strings.IndexAny(espec, "@<>="); i >= 0 {
+		return espec[:i], espec[i:]
+	}
+	return espec, ""
+}
+
+// dividirNodo separa un lenguaje concreto en nombre y versión, que puede estar vacía
+func dividirNodo(nodo string) (string, string) {
+	nombre, version, _ := strings.Cut(nodo, "@")
+	return nombre, version
+}
+
+// EspecificacionBase da el lenguaje que acepta el intérprete con su rango de versiones
+func (interp Interprete) EspecificacionBase() string {
+	return interp.LenguajeBase + interp.Versiones.String()
+}
+
+// EspecificacionOrigen da el lenguaje que acepta el traductor con su rango de versiones
+func (trad Traductor) EspecificacionOrigen() string {
+	return trad.LenguajeOrigen + trad.VersionesOrigen.String()
+}
+
+// interpreteDe arma un intérprete, con el costo por defecto, que acepta justo la versión del
+// lenguaje concreto dado
+func interpreteDe(nodo, lenguaje string) Interprete {
+	nombre, version := dividirNodo(nodo)
+	interp := Interprete{LenguajeBase: nombre, Lenguaje: lenguaje, Costo: COSTO_POR_DEFECTO}
+	if version != "" {
+		interp.Versiones = Rango{Min: version, Max: version, IncluyeMin: true, IncluyeMax: true}
+	}
+	return interp
+}
+
+// acepta indica si el intérprete puede correr programas en el lenguaje concreto dado
+func (interp Interprete) acepta(nodo string) bool {
+	nombre, version := dividirNodo(nodo)
+	return nombre == interp.LenguajeBase && interp.Versiones.Contiene(version)
+}
+
+// acepta indica si el traductor puede traducir programas en el lenguaje concreto dado
+func (trad Traductor) acepta(nodo string) bool {
+	nombre, version := dividirNodo(nodo)
+	return nombre == trad.LenguajeOrigen && trad.VersionesOrigen.Contiene(version)
+}
+
+// leerEntrada interpreta lo que acepta una herramienta: el nombre conocido del lenguaje y su
+// rango de versiones
+func (tc *Toolchain) leerEntrada(espec string) (string, Rango, error) {
+	nombre, resto := separarNombre(espec)
+	if nombre == "" {
+		return "", Rango{}, fmt.Errorf("falta el nombre del lenguaje en '%s'", espec)
+	}
+	rango, err := leerRango(resto)
+	if err != nil {
+		return "", Rango{}, fmt.Errorf("versión de %s: %v", nombre, err)
+	}
+	return tc.NombreLenguaje(nombre), rango, nil
+}
+
+// leerNodo interpreta un lenguaje concreto, sin versión o con "@versión"
+func (tc *Toolchain) leerNodo(espec string) (string, error) {
+	nombre, resto := separarNombre(espec)
+	if nombre == "" {
+		return "", fmt.Errorf("falta el nombre del lenguaje en '%s'", espec)
+	}
+	version, ok := strings.CutPrefix(resto, "@")
+	if resto != "" && (!ok || !versionValida(version)) {
+		return "", fmt.Errorf("%s debe ser un lenguaje sin versión o con una versión concreta (nombre@versión)", espec)
+	}
+	return tc.NombreLenguaje(nombre) + resto, nil
+}
+
+// registrar recuerda los nombres y los lenguajes concretos de una definición ya validada
+func (tc *Toolchain) registrar(nodos ...string) {
+	for _, nodo := range nodos {
+		nombre, _ := dividirNodo(nodo)
+		tc.registrarLenguaje(nombre)
+		tc.registrarNodo(nodo)
+	}
+}
+
+// registrarNodo agrega un lenguaje concreto al estado. Si alguna herramienta ya definida lo
+// puede llevar a una plataforma se marca como ejecutable en ella.
+func (tc *Toolchain) registrarNodo(nodo string) {
+	nombre, _ := dividirNodo(nodo)
+	if slices.Contains(tc.nodosDe[nombre], nodo) {
+		return
+	}
+	tc.nodosDe[nombre] = append(tc.nodosDe[nombre], nodo)
+	for _, plataforma := range tc.plataformas {
+		if tc.ejecutableEn(tc.estado, plataforma, nodo) {
+			tc.marcar(tc.estado, plataforma, nodo)
+		}
+	}
+}
+
+// nodos da los lenguajes del catálogo junto con todas las versiones concretas conocidas, ordenados
+func (tc *Toolchain) nodos() []string {
+	lista := tc.Lenguajes()
+	for _, nodos := range tc.nodosDe {
+		for _, nodo := range nodos {
+			if _, version := dividirNodo(nodo); version != "" && !slices.Contains(lista, nodo) {
+				lista = append(lista, nodo)
+			}
+		}
+	}
+	slices.Sort(lista)
+	return lista
+}
+
+// nodosCompatibles da los lenguajes concretos conocidos con ese nombre y una versión del rango
+func (tc *Toolchain) nodosCompatibles(nombre string, rango Rango) []string {
+	if rango == (Rango{}) {
+		return tc.nodosDe[nombre]
+	}
+	var nodos []string
+	for _, nodo := range tc.nodosDe[nombre] {
+		if _, version := dividirNodo(nodo); rango.Contiene(version) {
+			nodos = append(nodos, nodo)
+		}
+	}
+	return nodos
+}
+
+// interpretesPara da los intérpretes que aceptan el lenguaje concreto, en orden de definición.
+// Si todos lo aceptan, que es lo común, se devuelve el índice sin copiarlo.
+func (tc *Toolchain) interpretesPara(nodo string) []Interprete {
+	nombre, version := dividirNodo(nodo)
+	todos := tc.interpretesDe[nombre]
+	acepta := func(interp Interprete) bool { return interp.Versiones.Contiene(version) }
+	if !slices.ContainsFunc(todos, func(interp Interprete) bool { return !acepta(interp) }) {
+		return todos
+	}
+	var lista []Interprete
+	for _, interp := range todos {
+		if acepta(interp) {
+			lista = append(lista, interp)
+		}
+	}
+	return lista
+}
+
+// traductoresPara da los traductores que aceptan el lenguaje concreto, en orden de definición.
+// Igual que interpretesPara, si todos lo aceptan se devuelve el índice sin copiarlo.
+func (tc *Toolchain) traductoresPara(nodo string) []Traductor {
+	nombre, version := dividirNodo(nodo)
+	todos := tc.traductoresDesde[nombre]
+	acepta := func(trad Traductor) bool { return trad.VersionesOrigen.Contiene(version) }
+	if !slices.ContainsFunc(todos, func(trad Traductor) bool { return !acepta(trad) }) {
+		return todos
+	}
+	var lista []Traductor
+	for _, trad := range todos {
+		if acepta(trad) {
+			lista = append(lista, trad)
+		}
+	}
+	return lista
+}
+
+// ejecutableEn indica si el lenguaje concreto se ejecuta en la plataforma según el estado.
+// Un lenguaje que no aparece en ninguna definición no está en el estado, así que se revisa si
+// alguna herramienta que se puede usar lo acepta.
+func (tc *Toolchain) ejecutableEn(e *ejecutabilidad, plataforma, nodo string) bool {
+	if e.es(plataforma, nodo) {
+		return true
+	}
+	for _, interp := range tc.interpretesPara(nodo) {
+		if interpreteUsable(e, plataforma, interp) {
+			return true
+		}
+	}
+	for _, trad := range tc.traductoresPara(nodo) {
+		if traductorUsable(e, plataforma, trad) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+// Gabriel Seijas 19-00036
+package toolchain
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Prueba la lectura de rangos y qué versiones contienen
+func TestLeerRango(t *testing.T) {
+	casos := []struct {
+		texto     string
+		dentro    []string
+		fuera     []string
+		escritura string
+	}{
+		{"", []string{"", "2.7", "3.11"}, nil, ""},
+		{">=3.8", []string{"3.8", "3.8.0", "3.11", "4"}, []string{"3.7.9", ""}, ">=3.8"},
+		{">=3.8,<4", []string{"3.8", "3.12"}, []string{"4", "4.0.1", "2.7"}, ">=3.8,<4"},
+		{"@3.11", []string{"3.11", "3.11.0"}, []string{"3.1", "3.12"}, "@3.11"},
+		{"==3.11", []string{"3.11"}, []string{"3.10"}, "@3.11"},
+		{">2,<=3", []string{"2.1", "3"}, []string{"2", "3.0.1"}, ">2,<=3"},
+	}
+	for _, caso := range casos {
+		r, err := leerRango(caso.texto)
+		if err != nil {
+			t.Errorf("leerRango(%q) dio error: %v", caso.texto, err)
+			continue
+		}
+		for _, v := range caso.dentro {
+			if !r.Contiene(v) {
+				t.Errorf("%q debería contener la versión %q", caso.texto, v)
+			}
+		}
+		for _, v := range caso.fuera {
+			if r.Contiene(v) {
+				t.Errorf("%q no debería contener la versión %q", caso.texto, v)
+			}
+		}
+		if r.String() != caso.escritura {
+			t.Errorf("leerRango(%q).String() = %q, se esperaba %q", caso.texto, r.String(), caso.escritura)
+		}
+	}
+
+	for _, texto := range []string{">=", "~3", ">=3.x", ">=4,<3", "@-1", ">3,<3"} {
+		if _, err := leerRango(texto); err == nil {
+			t.Errorf("leerRango(%q) debería dar error", texto)
+		}
+	}
+}
+
+// Prueba que las rutas solo usan herramientas compatibles con la versión y la muestran
+func TestRutasConVersiones(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("C@11", LENGUAJE_LOCAL)
+	tc.DefinirInterprete("Python>=3.8", "C@11")
+	tc.DefinirPrograma("nuevo", "Python@3.11")
+	tc.DefinirPrograma("viejo", "Python@2.7")
+
+	if !tc.EsEjecutable("Python@3.11") || !tc.EsEjecutable("Python@3.8") {
+		t.Errorf("Python 3.11 y 3.8 deberían ser ejecutables")
+	}
+	if tc.EsEjecutable("Python@2.7") || tc.EsEjecutable("Python") {
+		t.Errorf("Python 2.7 y Python sin versión no cumplen el rango")
+	}
+	ruta, ok := tc.RutaMasCorta("Python@3.11")
+	if !ok || strings.Join(ruta.Lenguajes, " -> ") != "Python@3.11 -> C@11 -> LOCAL" {
+		t.Fatalf("Ruta incorrecta: %v, %v", ruta.Lenguajes, ok)
+	}
+	if !slices.Equal(ruta.Herramientas, []string{"intérprete de Python>=3.8 en C@11", "intérprete de C@11 en LOCAL"}) {
+		t.Errorf("Herramientas incorrectas: %v", ruta.Herramientas)
+	}
+	if _, ok := tc.RutaMasCorta("Python@2.7"); ok {
+		t.Errorf("No debería haber ruta para Python 2.7")
+	}
+	ejecutables := tc.ProgramasEjecutables(LENGUAJE_LOCAL)
+	if len(ejecutables) != 1 || ejecutables[0].Nombre != "nuevo" {
+		t.Errorf("Solo nuevo debería ser ejecutable: %v", ejecutables)
+	}
+
+	// Un traductor de 2.7 a 3.11 resuelve el programa viejo pasando por la versión nueva
+	tc.DefinirTraductor("C@11", "Python<3", "Python@3.11")
+	ruta, ok = tc.RutaMasCorta("Python@2.7")
+	if !ok || strings.Join(ruta.Lenguajes, " -> ") != "Python@2.7 -> Python@3.11 -> C@11 -> LOCAL" {
+		t.Errorf("Ruta del programa viejo incorrecta: %v, %v", ruta.Lenguajes, ok)
+	}
+}
+
+// Prueba que las versiones mal escritas y las repetidas dan error
+func TestDefinirConVersiones(t *testing.T) {
+	tc := New()
+	if err := tc.DefinirPrograma("app", "Python>=3"); err == nil {
+		t.Errorf("Un programa no puede estar en un rango de versiones")
+	}
+	if err := tc.DefinirInterprete("Python", "C>=11"); err == nil {
+		t.Errorf("Un intérprete tiene que estar hecho en una versión concreta")
+	}
+	if err := tc.DefinirTraductor("C", "Python>=3,<2", "C"); err == nil {
+		t.Errorf("Se aceptó un rango vacío")
+	}
+	if err := tc.DefinirPlataforma("ARM@v8"); err == nil || EsRepetido(err) {
+		t.Errorf("Se esperaba un error de versión inválida: %v", err)
+	}
+
+	tc.DefinirInterprete("Python>=3.8", LENGUAJE_LOCAL)
+	if err := tc.DefinirInterprete("python>=3.8.0", "local"); !EsRepetido(err) {
+		t.Errorf("No se detectó el intérprete repetido: %v", err)
+	}
+	if err := tc.DefinirInterprete("Python>=3.9", LENGUAJE_LOCAL); err != nil {
+		t.Errorf("Otro rango es otro intérprete: %v", err)
+	}
+	if err := tc.DefinirAlias("py", "Python@3"); err == nil {
+		t.Errorf("Un alias no puede apuntar a una versión")
+	}
+}
+
+// Prueba eliminar, implementar y guardar herramientas con rangos
+func TestHerramientasConVersiones(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("Python>=3", LENGUAJE_LOCAL)
+	tc.DefinirPrograma("app", "Python@3.12")
+	tc.DefinirCodigo("app", "2 3 + .")
+	if err := tc.ImplementarInterprete("Python>=3", LENGUAJE_LOCAL, Reescritura()); err != nil {
+		t.Fatalf("Error al implementar: %v", err)
+	}
+	if ejec, err := tc.Ejecutar("app", nil); err != nil || !slices.Equal(ejec.Salida, []int64{5}) {
+		t.Errorf("Ejecutar = %v, %v", ejec.Salida, err)
+	}
+
+	var buf bytes.Buffer
+	tc.Guardar(&buf)
+	if !strings.Contains(buf.String(), `"lenguaje_base": "Python>=3"`) {
+		t.Errorf("No se guardó el rango:\n%s", buf.String())
+	}
+	cargado, err := Cargar(&buf)
+	if err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+	if !cargado.EsEjecutable("Python@3.12") || cargado.EsEjecutable("Python@2") {
+		t.Errorf("El catálogo cargado no respeta el rango")
+	}
+
+	if _, err := tc.EliminarInterprete("Python", LENGUAJE_LOCAL); err == nil {
+		t.Errorf("Sin el rango es otro intérprete y no debería existir")
+	}
+	afectados, err := tc.EliminarInterprete("Python>=3", LENGUAJE_LOCAL)
+	if err != nil || len(afectados) != 1 || afectados[0].Nombre != "app" {
+		t.Errorf("EliminarInterprete = %v, %v", afectados, err)
+	}
+}
+
+// Prueba el diagnóstico y el bootstrapping con versiones concretas
+func TestAnalisisConVersiones(t *testing.T) {
+	tc := New()
+	tc.DefinirInterprete("Python>=3", "C@11")
+	diag := tc.Diagnosticar("Python@3.11", LENGUAJE_LOCAL)
+	var faltantes []string
+	for _, f := range diag.Faltantes {
+		faltantes = append(faltantes, f.EspecificacionBase())
+	}
+	if !slices.Equal(faltantes, []string{"C@11", "Python@3.11"}) {
+		t.Errorf("Se esperaba que faltara un intérprete de C@11 o de Python@3.11: %v", faltantes)
+	}
+
+	tc.DefinirTraductor("Rust@1.70", "Rust>=1.60", LENGUAJE_LOCAL)
+	analisis := tc.AnalizarBootstrapping(LENGUAJE_LOCAL)
+	if !slices.Equal(analisis.Autoalojadas, []string{"traductor de Rust@1.70 de Rust>=1.60 a LOCAL"}) {
+		t.Errorf("Autoalojadas incorrectas: %v", analisis.Autoalojadas)
+	}
+	if len(analisis.Ciclos) != 1 || !slices.Equal(analisis.Ciclos[0].Semillas, []string{"Rust@1.70"}) {
+		t.Errorf("Se esperaba sembrar Rust@1.70: %+v", analisis.Ciclos)
+	}
+}
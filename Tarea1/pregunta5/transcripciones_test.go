@@ -0,0 +1,22 @@
+// Gabriel Seijas 19-00036
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"comandos/transcripcion"
+	"pregunta5/toolchain"
+)
+
+// Corre las sesiones de testdata, cada una sobre un catálogo vacío. Después de cambiar un
+// mensaje a propósito se regeneran con: go test -run TestTranscripciones -update
+func TestTranscripciones(t *testing.T) {
+	transcripcion.Probar(t, filepath.Join("testdata", "*.txt"), func(salida io.Writer) transcripcion.Sesion {
+		tabla := comandosSimulador(salida, toolchain.New())
+		return func(linea string) bool {
+			return procesarLinea(salida, tabla, linea) == resultadoSalir
+		}
+	})
+}